@@ -0,0 +1,77 @@
+// cmd/fakepay/main.go
+//
+// fakepay mengirim notifikasi pembayaran bertanda tangan ke webhook gateway palsu (PAYMENT_GATEWAY=fake),
+// untuk menyelesaikan atau menggagalkan order saat pengembangan tanpa payment gateway sungguhan.
+//
+//	go run ./cmd/fakepay -order ORD-20241019-9F3A2C1B -amount 164000
+//	go run ./cmd/fakepay -order ORD-20241019-9F3A2C1B -status failed
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/mfuadfakhruzzaki/backend-api/payment"
+)
+
+func main() {
+	// Secret diambil dari .env/environment, sama seperti server
+	_ = godotenv.Load()
+	secret := os.Getenv("PAYMENT_FAKE_SECRET")
+
+	url := flag.String("url", "http://localhost:8080/webhooks/payments/fake", "URL webhook pembayaran")
+	order := flag.String("order", "", "nomor order (order_number)")
+	status := flag.String("status", string(payment.StatusPaid), "status pembayaran: paid, pending, failed atau expired")
+	amount := flag.Int64("amount", 0, "nominal yang dibayar dalam rupiah bulat (harus sama dengan nominal order untuk status paid)")
+	eventID := flag.String("event", "", "ID notifikasi; kirim ulang ID yang sama untuk menguji idempotensi")
+	flag.Parse()
+
+	if *order == "" {
+		fmt.Fprintln(os.Stderr, "-order wajib diisi")
+		os.Exit(2)
+	}
+	if *eventID == "" {
+		*eventID = fmt.Sprintf("%s:%s:%d", *order, *status, time.Now().UnixNano())
+	}
+
+	gateway, err := payment.NewFakeGateway(secret, "")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	body, _ := json.Marshal(payment.FakeNotification{
+		EventID:     *eventID,
+		OrderNumber: *order,
+		Reference:   "fake-" + *order,
+		Status:      payment.Status(*status),
+		Amount:      *amount,
+	})
+	req, err := http.NewRequest(http.MethodPost, *url, bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(payment.FakeSignatureHeader, hex.EncodeToString(gateway.Sign(body)))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gagal mengirim notifikasi:", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+	response, _ := io.ReadAll(resp.Body)
+	fmt.Printf("%s\n%s\n", resp.Status, response)
+	if resp.StatusCode >= 300 {
+		os.Exit(1)
+	}
+}
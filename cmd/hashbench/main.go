@@ -0,0 +1,69 @@
+// cmd/hashbench/main.go
+//
+// hashbench mengukur waktu pembuatan dan verifikasi hash password di mesin saat ini, untuk membantu
+// memilih parameter Argon2id (ARGON2_MEMORY_KIB, ARGON2_ITERATIONS, ARGON2_THREADS).
+//
+//	go run ./cmd/hashbench -n 20
+//	go run ./cmd/hashbench -memory 65536 -iterations 3 -threads 2
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func main() {
+	// Parameter bawaan diambil dari .env/environment, sama seperti server
+	_ = godotenv.Load()
+	current := utils.CurrentArgon2Params()
+
+	rounds := flag.Int("n", 10, "jumlah hash per pengukuran")
+	memory := flag.Uint("memory", uint(current.Memory), "memori Argon2id dalam KiB")
+	iterations := flag.Uint("iterations", uint(current.Time), "jumlah iterasi Argon2id")
+	threads := flag.Uint("threads", uint(current.Threads), "jumlah thread Argon2id")
+	bcryptCost := flag.Int("bcrypt-cost", 14, "cost bcrypt sebagai pembanding (0 untuk melewati)")
+	flag.Parse()
+
+	if *rounds < 1 || *memory == 0 || *iterations == 0 || *threads == 0 || *threads > 255 {
+		fmt.Fprintln(os.Stderr, "parameter tidak valid")
+		os.Exit(2)
+	}
+
+	params := utils.Argon2Params{Memory: uint32(*memory), Time: uint32(*iterations), Threads: uint8(*threads)}
+	const password = "correct horse battery staple"
+
+	hash, err := utils.HashArgon2id(password, params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gagal membuat hash:", err)
+		os.Exit(1)
+	}
+
+	report(fmt.Sprintf("argon2id m=%d KiB t=%d p=%d (hash)", params.Memory, params.Time, params.Threads), *rounds, func() {
+		_, _ = utils.HashArgon2id(password, params)
+	})
+	report(fmt.Sprintf("argon2id m=%d KiB t=%d p=%d (verify)", params.Memory, params.Time, params.Threads), *rounds, func() {
+		utils.CheckPasswordHash(password, hash)
+	})
+
+	if *bcryptCost > 0 {
+		report(fmt.Sprintf("bcrypt cost=%d (hash)", *bcryptCost), *rounds, func() {
+			_, _ = bcrypt.GenerateFromPassword([]byte(password), *bcryptCost)
+		})
+	}
+}
+
+// report menjalankan fn sebanyak rounds kali lalu mencetak rata-rata waktu per hash
+func report(name string, rounds int, fn func()) {
+	start := time.Now()
+	for i := 0; i < rounds; i++ {
+		fn()
+	}
+	elapsed := time.Since(start)
+	fmt.Printf("%-45s %10s/hash  (%d hash dalam %s)\n", name, (elapsed / time.Duration(rounds)).Round(time.Microsecond), rounds, elapsed.Round(time.Millisecond))
+}
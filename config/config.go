@@ -2,23 +2,31 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"sync/atomic"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
 	"github.com/mfuadfakhruzzaki/backend-api/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 var DB *gorm.DB
 
+// migrated bernilai true setelah migrasi skema berhasil dijalankan
+var migrated atomic.Bool
+
 // ConnectDatabase menghubungkan ke database dan memuat environment variables
 func ConnectDatabase() {
 	// Memuat file .env
 	err := godotenv.Load()
 	if err != nil {
-		log.Println("Peringatan: Gagal memuat file .env, menggunakan variabel lingkungan")
+		slog.Debug("Gagal memuat file .env, menggunakan variabel lingkungan")
 	}
 
 	// Mengambil variabel lingkungan untuk koneksi database
@@ -31,26 +39,109 @@ func ConnectDatabase() {
 	// Data Source Name (DSN) untuk PostgreSQL
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai",
 		dbHost, dbUser, dbPassword, dbName, dbPort)
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormLogger(),
+	})
 
 	// Jika gagal terhubung ke database, panic
 	if err != nil {
-		panic("Gagal terhubung ke database!")
+		slog.Error("Gagal terhubung ke database!", "error", err)
+		os.Exit(1)
+	}
+
+	// Mencatat durasi query dan statistik pool koneksi ke Prometheus
+	if err := database.Use(metrics.GormPlugin{}); err != nil {
+		slog.Warn("Gagal memasang plugin metrics GORM", "error", err)
+	}
+	if err := metrics.RegisterDBStats(database, dbName); err != nil {
+		slog.Warn("Gagal mendaftarkan metrics pool database", "error", err)
+	}
+
+	// Membuat span untuk setiap query (tanpa nilai parameter agar data pribadi tidak ikut tercatat)
+	if err := database.Use(gormtracing.NewPlugin(
+		gormtracing.WithDBName(dbName),
+		gormtracing.WithoutMetrics(),
+		gormtracing.WithoutQueryVariables(),
+	)); err != nil {
+		slog.Warn("Gagal memasang plugin tracing GORM", "error", err)
 	}
 
 	// Simpan koneksi database ke variabel global
 	DB = database
-	fmt.Println("Database berhasil terhubung!")
+	slog.Info("Database berhasil terhubung!")
 
 	// Jalankan migrasi untuk menyesuaikan model ke database
 	Migrate()
 }
 
+// gormLogger mengarahkan log GORM ke slog. Query ditulis dalam bentuk berparameter
+// sehingga nilai seperti email atau kode verifikasi tidak ikut tercatat.
+func gormLogger() gormlogger.Interface {
+	return gormlogger.New(
+		slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+		gormlogger.Config{
+			SlowThreshold:             GetEnvDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			LogLevel:                  gormlogger.Warn,
+			IgnoreRecordNotFoundError: true,
+			ParameterizedQueries:      true,
+		},
+	)
+}
+
+// CloseDatabase menutup pool koneksi database
+func CloseDatabase() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// MigratedModels mengembalikan daftar model yang skemanya dikelola oleh Migrate
+func MigratedModels() []interface{} {
+	return []interface{}{
+		&models.Package{},
+		&models.User{},
+		&models.RecoveryCode{},
+		&models.UserIdentity{},
+		&models.LoginCode{},
+		&models.Session{},
+		&models.EmailChange{},
+		&models.Line{},
+		&models.PhoneVerification{},
+		&models.Order{},
+		&models.PaymentNotification{},
+		&models.Invoice{},
+		&models.InvoiceCounter{},
+		&models.LedgerAccount{},
+		&models.JournalEntry{},
+		&models.LedgerEntry{},
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+	}
+}
+
 // Migrate menjalankan migrasi skema database berdasarkan model yang ada
 func Migrate() {
-	err := DB.AutoMigrate(&models.User{})
+	err := beforeAutoMigrate()
+	if err == nil {
+		err = DB.AutoMigrate(MigratedModels()...)
+	}
+	if err == nil {
+		err = afterAutoMigrate()
+	}
 	if err != nil {
-		log.Fatalf("Gagal melakukan migrasi database: %v", err)
+		slog.Error("Gagal melakukan migrasi database", "error", err)
+		os.Exit(1)
 	}
-	fmt.Println("Migrasi database berhasil!")
+	migrated.Store(true)
+	slog.Info("Migrasi database berhasil!")
+}
+
+// MigrationsApplied melaporkan apakah migrasi sudah berhasil dijalankan oleh proses ini
+func MigrationsApplied() bool {
+	return migrated.Load()
 }
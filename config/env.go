@@ -0,0 +1,81 @@
+// config/env.go
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetEnv mengembalikan nilai environment variable atau nilai default jika kosong
+func GetEnv(key, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GetEnvInt membaca environment variable sebagai integer
+func GetEnvInt(key string, fallback int) int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("Nilai environment variable tidak valid, menggunakan default", "key", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// GetEnvBool membaca environment variable sebagai boolean
+func GetEnvBool(key string, fallback bool) bool {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("Nilai environment variable tidak valid, menggunakan default", "key", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// GetEnvDuration membaca environment variable sebagai durasi (misalnya "30s" atau "2m")
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("Nilai environment variable tidak valid, menggunakan default", "key", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// GetEnvList membaca environment variable berisi daftar yang dipisah koma
+func GetEnvList(key string, fallback []string) []string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IsDevelopment melaporkan apakah server berjalan untuk pengembangan lokal (APP_ENV=development).
+// Default-nya bukan development agar deployment yang lupa mengatur APP_ENV tetap memakai pengaturan aman.
+func IsDevelopment() bool {
+	return strings.EqualFold(strings.TrimSpace(GetEnv("APP_ENV", "production")), "development")
+}
@@ -0,0 +1,215 @@
+// config/migrations.go
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"gorm.io/gorm"
+)
+
+// beforeAutoMigrate menyiapkan data lama agar index baru dari AutoMigrate dapat dibuat
+func beforeAutoMigrate() error {
+	if err := dropLegacyIndexes(); err != nil {
+		return err
+	}
+	if err := convertAmountColumns(); err != nil {
+		return err
+	}
+	return normalizePhoneNumbers()
+}
+
+// afterAutoMigrate mengisi kolom baru yang nilainya diturunkan dari data yang sudah ada
+func afterAutoMigrate() error {
+	if err := fillPhoneOperators(); err != nil {
+		return err
+	}
+	if err := migratePhonesToLines(); err != nil {
+		return err
+	}
+	if err := fillOrderOriginalAmounts(); err != nil {
+		return err
+	}
+	return promoteAdmins()
+}
+
+// dropLegacyIndexes menghapus unique index lama pada email dan username yang juga mencakup akun yang
+// sudah dihapus. AutoMigrate kemudian membuat index parsial (WHERE deleted_at IS NULL) sebagai gantinya.
+func dropLegacyIndexes() error {
+	migrator := DB.Migrator()
+	for _, name := range []string{"idx_users_email", "idx_users_username"} {
+		if !migrator.HasIndex(&models.User{}, name) {
+			continue
+		}
+		if err := migrator.DropIndex(&models.User{}, name); err != nil {
+			return err
+		}
+		slog.Info("Index lama dihapus", "index", name)
+	}
+	return nil
+}
+
+// convertAmountColumns mengubah kolom harga lama (float/numeric) menjadi bigint dalam minor unit.
+// Rupiah tidak memiliki pecahan, sehingga nilai lama dipindahkan apa adanya; migrasi dibatalkan jika
+// ada nilai berpecahan agar tidak ada nominal yang berubah diam-diam karena pembulatan.
+func convertAmountColumns() error {
+	columns := []struct {
+		model  interface{}
+		table  string
+		column string
+	}{
+		{&models.Package{}, "packages", "price"},
+		{&models.Order{}, "orders", "amount"},
+	}
+
+	migrator := DB.Migrator()
+	for _, c := range columns {
+		if !migrator.HasTable(c.model) {
+			continue
+		}
+		columnTypes, err := migrator.ColumnTypes(c.model)
+		if err != nil {
+			return err
+		}
+		for _, columnType := range columnTypes {
+			if columnType.Name() != c.column {
+				continue
+			}
+			switch strings.ToLower(columnType.DatabaseTypeName()) {
+			case "int8", "bigint":
+				continue
+			}
+
+			var fractional int64
+			if err := DB.Table(c.table).Where(fmt.Sprintf("%s <> ROUND(%s)", c.column, c.column)).Count(&fractional).Error; err != nil {
+				return err
+			}
+			if fractional > 0 {
+				return fmt.Errorf("%d baris pada %s.%s memiliki nominal berpecahan; perbaiki secara manual sebelum migrasi", fractional, c.table, c.column)
+			}
+			if err := DB.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING %s::bigint", c.table, c.column, c.column)).Error; err != nil {
+				return err
+			}
+			slog.Info("Kolom nominal diubah menjadi bigint", "table", c.table, "column", c.column)
+		}
+	}
+	return nil
+}
+
+// normalizePhoneNumbers mengubah nomor telepon lama ke format E.164 sebelum unique index dibuat.
+// Jika beberapa akun memakai nomor yang sama, nomor hanya dipertahankan pada akun yang paling lama.
+func normalizePhoneNumbers() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.User{}) || migrator.HasIndex(&models.User{}, "idx_users_phone_active") {
+		return nil
+	}
+
+	var users []struct {
+		ID          uint
+		PhoneNumber string
+	}
+	if err := DB.Model(&models.User{}).Select("id, phone_number").Where("phone_number <> ''").Order("id").Find(&users).Error; err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(users))
+	for _, user := range users {
+		phone, err := utils.NormalizePhoneNumber(user.PhoneNumber)
+		if err != nil {
+			// Nomor yang tidak valid tetap disimpan apa adanya dan harus diperbarui oleh pengguna
+			phone = user.PhoneNumber
+		}
+		if seen[phone] {
+			slog.Warn("Nomor telepon duplikat dikosongkan", "user_id", user.ID)
+			phone = ""
+		}
+		seen[phone] = phone != ""
+
+		if phone != user.PhoneNumber {
+			if err := DB.Model(&models.User{}).Where("id = ?", user.ID).Update("phone_number", phone).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fillPhoneOperators mendeteksi operator untuk nomor yang belum memiliki operator
+func fillPhoneOperators() error {
+	var users []models.User
+	if err := DB.Select("id, phone_number").Where("phone_number <> '' AND (phone_operator = '' OR phone_operator IS NULL)").Find(&users).Error; err != nil {
+		return err
+	}
+	for _, user := range users {
+		if operator := utils.DetectOperator(user.PhoneNumber); operator != "" {
+			if err := DB.Model(&models.User{}).Where("id = ?", user.ID).Update("phone_operator", operator).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migratePhonesToLines membuat line utama dari User.PhoneNumber untuk pengguna yang belum memiliki line,
+// beserta status verifikasi dan paket yang sedang dipilih
+func migratePhonesToLines() error {
+	var users []models.User
+	if err := DB.Where("phone_number <> '' AND NOT EXISTS (SELECT 1 FROM lines WHERE lines.user_id = users.id)").Find(&users).Error; err != nil {
+		return err
+	}
+	for _, user := range users {
+		if _, err := utils.NormalizePhoneNumber(user.PhoneNumber); err != nil {
+			slog.Warn("Nomor telepon tidak valid, line utama tidak dibuat", "user_id", user.ID)
+			continue
+		}
+		line := models.Line{
+			UserID:    user.ID,
+			MSISDN:    user.PhoneNumber,
+			Operator:  user.PhoneOperator,
+			Verified:  user.PhoneVerified,
+			IsPrimary: true,
+			PackageID: user.PackageID,
+		}
+		if err := DB.Create(&line).Error; err != nil {
+			return err
+		}
+	}
+	if len(users) > 0 {
+		slog.Info("Nomor telepon dipindahkan ke line utama", "count", len(users))
+	}
+	return nil
+}
+
+// fillOrderOriginalAmounts mengisi harga sebelum diskon untuk order yang dibuat sebelum ada kode promo
+func fillOrderOriginalAmounts() error {
+	result := DB.Model(&models.Order{}).Where("original_amount = 0 AND discount = 0 AND amount <> 0").
+		Update("original_amount", gorm.Expr("amount"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		slog.Info("Harga sebelum diskon order diisi", "count", result.RowsAffected)
+	}
+	return nil
+}
+
+// promoteAdmins memberi peran admin kepada akun dengan email di ADMIN_EMAILS yang sudah diverifikasi, agar
+// alamat admin yang belum diklaim tidak dapat didaftarkan orang lain untuk memperoleh peran admin. Peran tidak
+// dicabut otomatis jika email dihapus dari daftar.
+func promoteAdmins() error {
+	emails := GetEnvList("ADMIN_EMAILS", nil)
+	if len(emails) == 0 {
+		return nil
+	}
+	result := DB.Model(&models.User{}).Where("email IN ? AND email_verified = ? AND role <> ?", emails, true, models.RoleAdmin).Update("role", models.RoleAdmin)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		slog.Info("Peran admin diberikan", "count", result.RowsAffected)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// config/password.go
+package config
+
+// PasswordPolicy berisi aturan password baru yang dibaca dari environment variables
+type PasswordPolicy struct {
+	MinLength int
+	// MaxLength (dalam byte) membatasi biaya hashing untuk password yang sangat panjang
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// DisallowPersonalInfo menolak password yang memuat email atau username pengguna
+	DisallowPersonalInfo bool
+	// BreachedPasswordsPath menunjuk ke daftar hash SHA-1 password yang bocor (kosong untuk menonaktifkan)
+	BreachedPasswordsPath string
+}
+
+// LoadPasswordPolicy membaca kebijakan password dari environment variables
+func LoadPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:             GetEnvInt("PASSWORD_MIN_LENGTH", 8),
+		MaxLength:             GetEnvInt("PASSWORD_MAX_LENGTH", 128),
+		RequireUpper:          GetEnvBool("PASSWORD_REQUIRE_UPPER", true),
+		RequireLower:          GetEnvBool("PASSWORD_REQUIRE_LOWER", true),
+		RequireDigit:          GetEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol:         GetEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		DisallowPersonalInfo:  GetEnvBool("PASSWORD_DISALLOW_PERSONAL_INFO", true),
+		BreachedPasswordsPath: GetEnv("BREACHED_PASSWORDS_PATH", ""),
+	}
+}
@@ -0,0 +1,107 @@
+// config/security.go
+package config
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CORSConfig berisi kebijakan CORS yang dibaca dari environment variables
+type CORSConfig struct {
+	// AllowedOrigins berisi origin lengkap (https://app.example.com), pola subdomain
+	// (https://*.example.com) atau "*" untuk semua origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// LoadCORSConfig membaca konfigurasi CORS dari environment variables
+func LoadCORSConfig() CORSConfig {
+	cfg := CORSConfig{
+		AllowedOrigins:   GetEnvList("CORS_ALLOWED_ORIGINS", []string{"*"}),
+		AllowedMethods:   GetEnvList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+		AllowedHeaders:   GetEnvList("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Authorization", "Accept-Language", "X-Request-ID", "X-Device-Name", "traceparent", "tracestate"}),
+		ExposedHeaders:   GetEnvList("CORS_EXPOSED_HEADERS", []string{"Content-Length", "Content-Language", "X-Request-ID", "X-Trace-ID", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"}),
+		AllowCredentials: GetEnvBool("CORS_ALLOW_CREDENTIALS", false),
+		MaxAge:           GetEnvDuration("CORS_MAX_AGE", 12*time.Hour),
+	}
+
+	// Browser menolak kombinasi origin "*" dengan credentials, jadi credentials dimatikan
+	if cfg.AllowsAllOrigins() && cfg.AllowCredentials {
+		slog.Warn("CORS_ALLOWED_ORIGINS berisi \"*\", CORS_ALLOW_CREDENTIALS dinonaktifkan")
+		cfg.AllowCredentials = false
+	}
+	return cfg
+}
+
+// AllowsAllOrigins mengembalikan true jika semua origin diizinkan
+func (c CORSConfig) AllowsAllOrigins() bool {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// OriginAllowed memeriksa origin terhadap daftar yang diizinkan. Pola "https://*.example.com"
+// cocok dengan subdomain apa pun dari example.com (tetapi tidak dengan example.com itu sendiri)
+// dengan skema dan port yang sama.
+func (c CORSConfig) OriginAllowed(origin string) bool {
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return false
+	}
+
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+
+		pattern, err := url.Parse(allowed)
+		if err != nil || !strings.HasPrefix(pattern.Host, "*.") {
+			continue
+		}
+		if !strings.EqualFold(pattern.Scheme, parsed.Scheme) || pattern.Port() != parsed.Port() {
+			continue
+		}
+		suffix := strings.ToLower(strings.TrimPrefix(pattern.Hostname(), "*"))
+		if strings.HasSuffix(strings.ToLower(parsed.Hostname()), suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityConfig berisi konfigurasi header keamanan dan batas ukuran body request
+type SecurityConfig struct {
+	// HSTSMaxAge adalah nilai max-age untuk Strict-Transport-Security (0 untuk menonaktifkan)
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+
+	// ContentSecurityPolicy berlaku untuk respons API, SwaggerContentSecurityPolicy untuk /swagger
+	ContentSecurityPolicy        string
+	SwaggerContentSecurityPolicy string
+
+	// MaxBodyBytes membatasi ukuran body request selain multipart/form-data
+	MaxBodyBytes int64
+}
+
+// LoadSecurityConfig membaca konfigurasi header keamanan dari environment variables
+func LoadSecurityConfig() SecurityConfig {
+	return SecurityConfig{
+		HSTSMaxAge:            GetEnvDuration("HSTS_MAX_AGE", 365*24*time.Hour),
+		HSTSIncludeSubdomains: GetEnvBool("HSTS_INCLUDE_SUBDOMAINS", true),
+		HSTSPreload:           GetEnvBool("HSTS_PRELOAD", false),
+		ContentSecurityPolicy: GetEnv("CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'"),
+		SwaggerContentSecurityPolicy: GetEnv("SWAGGER_CONTENT_SECURITY_POLICY",
+			"default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'"),
+		MaxBodyBytes: int64(GetEnvInt("MAX_BODY_BYTES", 1<<20)),
+	}
+}
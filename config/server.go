@@ -0,0 +1,54 @@
+// config/server.go
+package config
+
+import "time"
+
+// ServerConfig berisi konfigurasi http.Server yang dibaca dari environment variables
+type ServerConfig struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	// TLS diaktifkan jika TLSCertFile dan TLSKeyFile diisi
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// H2C mengaktifkan HTTP/2 tanpa TLS (cleartext), misalnya di belakang load balancer
+	H2C bool
+
+	// DrainDelay adalah jeda antara readiness false dan penghentian listener
+	DrainDelay time.Duration
+	// ShutdownTimeout membatasi waktu menunggu request yang sedang berjalan selesai
+	ShutdownTimeout time.Duration
+}
+
+// LoadServerConfig membaca konfigurasi server dari environment variables
+func LoadServerConfig() ServerConfig {
+	return ServerConfig{
+		Addr:              GetEnv("SERVER_ADDR", ":"+GetEnv("PORT", "8080")),
+		ReadTimeout:       GetEnvDuration("SERVER_READ_TIMEOUT", 60*time.Second),
+		ReadHeaderTimeout: GetEnvDuration("SERVER_READ_HEADER_TIMEOUT", 10*time.Second),
+		WriteTimeout:      GetEnvDuration("SERVER_WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:       GetEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+		MaxHeaderBytes:    GetEnvInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+		TLSCertFile:       GetEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:        GetEnv("TLS_KEY_FILE", ""),
+		H2C:               GetEnvBool("SERVER_H2C", false),
+		DrainDelay:        GetEnvDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		ShutdownTimeout:   GetEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	}
+}
+
+// TLSEnabled melaporkan apakah server harus berjalan dengan TLS
+func (cfg ServerConfig) TLSEnabled() bool {
+	return cfg.TLSCertFile != "" && cfg.TLSKeyFile != ""
+}
+
+// TrustedProxies membaca daftar IP/CIDR proxy tepercaya dari TRUSTED_PROXIES (dipisah koma).
+// Jika kosong, header X-Forwarded-For diabaikan dan IP koneksi langsung yang dipakai.
+func TrustedProxies() []string {
+	return GetEnvList("TRUSTED_PROXIES", nil)
+}
@@ -0,0 +1,12 @@
+// config/version.go
+package config
+
+// Informasi build yang diisi saat kompilasi, misalnya:
+//
+//	go build -ldflags "-X github.com/mfuadfakhruzzaki/backend-api/config.GitCommit=$(git rev-parse HEAD) \
+//	  -X github.com/mfuadfakhruzzaki/backend-api/config.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+	Version   = "dev"
+)
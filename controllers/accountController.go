@@ -0,0 +1,290 @@
+// controllers/accountController.go
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"github.com/mfuadfakhruzzaki/backend-api/wallet"
+	"gorm.io/gorm"
+)
+
+// errWalletNotEmpty aborts account deletion while the wallet still holds a balance
+var errWalletNotEmpty = errors.New("wallet balance is not empty")
+
+// DeleteAccountRequest represents the request body for deleting the account
+type DeleteAccountRequest struct {
+	// Password may be omitted only by accounts that have no password (e.g. created with Google)
+	Password string `json:"password"`
+}
+
+// DeleteAccount deletes the account of the authenticated user
+// @Summary Delete account
+// @Description Soft-deletes the account after confirming the password, ends every session and unlinks OpenID Connect identities. Personal data, the profile picture and login history are permanently removed after the grace period (ACCOUNT_DELETION_GRACE_PERIOD); invoices are kept for tax purposes with the buyer details anonymized.
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param   request  body  DeleteAccountRequest  true  "Current password"
+// @Success 200 {object} SuccessResponse "Account deleted"
+// @Failure 401 {object} ErrorResponse "Wrong password"
+// @Failure 409 {object} ErrorResponse "Wallet balance must be used or refunded first"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Failed to delete account"
+// @Router  /api/users/account [delete]
+func DeleteAccount(c *gin.Context) {
+	var input DeleteAccountRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	// Password confirmation shares the login attempt budget and lockout
+	if user.Password != "" {
+		if !guardAccount(c, "login", user.Email, ratelimit.LoginPerAccount) {
+			return
+		}
+		if !utils.CheckPasswordHash(input.Password, user.Password) {
+			recordAccountFailure(c, "login", user.Email, ratelimit.LoginLockout)
+			metrics.AccountDeletionsTotal.WithLabelValues("request", "invalid_password").Inc()
+			respondError(c, http.StatusUnauthorized, i18n.MsgInvalidPassword)
+			return
+		}
+		resetAccountFailures(c, "login", user.Email)
+	}
+
+	var balance money.Money
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// A remaining wallet balance would be lost with the account, so it has to be used or refunded first
+		var err error
+		if balance, err = wallet.UserBalance(tx, user.ID, money.IDR); err != nil {
+			return err
+		}
+		if balance.IsPositive() {
+			return errWalletNotEmpty
+		}
+		if err := tx.Delete(&user).Error; err != nil {
+			return err
+		}
+		// Lines are soft-deleted too so that their numbers can be registered by another account, and provider
+		// identities are removed so that the same provider account can sign up again
+		for _, pending := range []interface{}{
+			&models.LoginCode{}, &models.EmailChange{}, &models.PhoneVerification{}, &models.Line{}, &models.UserIdentity{},
+		} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(pending).Error; err != nil {
+				return err
+			}
+		}
+		_, err = revokeSessions(tx, user.ID, 0)
+		return err
+	})
+	if errors.Is(err, errWalletNotEmpty) {
+		metrics.AccountDeletionsTotal.WithLabelValues("request", "wallet_not_empty").Inc()
+		respondError(c, http.StatusConflict, i18n.MsgDeleteAccountWalletNotEmpty, balance.Format())
+		return
+	}
+	metrics.AccountDeletionsTotal.WithLabelValues("request", metrics.Result(err)).Inc()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDeleteAccountFailed)
+		return
+	}
+
+	gracePeriod := services.AccountDeletionGracePeriod()
+	userID, email, language := user.ID, user.Email, user.Language
+	ctx := context.WithoutCancel(c.Request.Context())
+	services.StartWorker("account-deleted-email", func(context.Context) {
+		if err := utils.SendAccountDeletedEmail(ctx, email, gracePeriod, language); err != nil {
+			slog.ErrorContext(ctx, "Gagal mengirim konfirmasi penghapusan akun", "user_id", userID, "error", err)
+		}
+	})
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgAccountDeleted, int(gracePeriod.Hours()/24)),
+	})
+}
+
+// ExportAccountData returns the personal data of the authenticated user as a ZIP archive
+// @Summary Export personal data
+// @Description Returns a ZIP archive with the profile, subscription, linked accounts and login history as JSON and CSV files.
+// @Tags User
+// @Produce  application/zip
+// @Success 200 {file} file "ZIP archive"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Failed to export data"
+// @Router  /api/users/export [get]
+func ExportAccountData(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	archive, err := buildDataExport(config.DB.WithContext(c.Request.Context()), &user)
+	metrics.DataExportsTotal.WithLabelValues(metrics.Result(err)).Inc()
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Gagal membuat ekspor data", "user_id", user.ID, "error", err)
+		respondError(c, http.StatusInternalServerError, i18n.MsgDataExportFailed)
+		return
+	}
+
+	filename := fmt.Sprintf("data-export-%d-%s.zip", user.ID, time.Now().UTC().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// buildDataExport collects the user's data into an in-memory ZIP so that errors can still be reported as JSON
+func buildDataExport(db *gorm.DB, user *models.User) ([]byte, error) {
+	var subscription *models.Package
+	if user.PackageID != nil {
+		var pkg models.Package
+		if err := db.Where("id = ?", *user.PackageID).First(&pkg).Error; err == nil {
+			subscription = &pkg
+		} else if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	var identities []models.UserIdentity
+	if err := db.Where("user_id = ?", user.ID).Order("created_at").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	var lines []models.Line
+	if err := db.Where("user_id = ?", user.ID).Order("created_at").Find(&lines).Error; err != nil {
+		return nil, err
+	}
+	var orders []models.Order
+	if err := db.Where("user_id = ?", user.ID).Order("created_at").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	var invoices []models.Invoice
+	if err := db.Where("user_id = ?", user.ID).Order("issued_at").Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+	// Limit -1 mengambil seluruh mutasi wallet
+	walletTransactions, _, err := wallet.History(db, user.ID, money.IDR, -1, 0)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []models.Session
+	if err := db.Where("user_id = ?", user.ID).Order("created_at").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	var emailChanges []models.EmailChange
+	if err := db.Where("user_id = ? AND confirmed_at IS NOT NULL", user.ID).Order("created_at").Find(&emailChanges).Error; err != nil {
+		return nil, err
+	}
+
+	loginHistory := [][]string{{"signed_in_at", "last_seen_at", "expires_at", "revoked_at", "device_name", "user_agent", "ip_address"}}
+	for _, session := range sessions {
+		loginHistory = append(loginHistory, []string{
+			formatExportTime(&session.CreatedAt), formatExportTime(&session.LastSeenAt), formatExportTime(&session.ExpiresAt),
+			formatExportTime(session.RevokedAt), session.DeviceName, session.UserAgent, session.IPAddress,
+		})
+	}
+	emailHistory := [][]string{{"changed_at", "old_email", "new_email", "undone_at"}}
+	for _, change := range emailChanges {
+		emailHistory = append(emailHistory, []string{
+			formatExportTime(change.ConfirmedAt), change.OldEmail, change.NewEmail, formatExportTime(change.UndoneAt),
+		})
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	err = writeExportJSON(archive, "profile.json", gin.H{
+		"id":                 user.ID,
+		"email":              user.Email,
+		"username":           user.Username,
+		"phone_number":       user.PhoneNumber,
+		"phone_operator":     user.PhoneOperator,
+		"phone_verified":     user.PhoneVerified,
+		"profile_picture":    user.ProfilePicture,
+		"email_verified":     user.EmailVerified,
+		"language":           user.Language,
+		"two_factor_enabled": user.TwoFactorEnabled,
+		"has_password":       user.Password != "",
+		"created_at":         user.CreatedAt,
+		"updated_at":         user.UpdatedAt,
+	})
+	if err == nil {
+		err = writeExportJSON(archive, "subscription.json", gin.H{"package_id": user.PackageID, "package": subscription})
+	}
+	if err == nil {
+		err = writeExportJSON(archive, "lines.json", lines)
+	}
+	if err == nil {
+		err = writeExportJSON(archive, "orders.json", orders)
+	}
+	if err == nil {
+		err = writeExportJSON(archive, "invoices.json", invoices)
+	}
+	if err == nil {
+		err = writeExportJSON(archive, "wallet_transactions.json", walletTransactions)
+	}
+	if err == nil {
+		err = writeExportJSON(archive, "linked_accounts.json", identities)
+	}
+	if err == nil {
+		err = writeExportCSV(archive, "login_history.csv", loginHistory)
+	}
+	if err == nil {
+		err = writeExportCSV(archive, "email_changes.csv", emailHistory)
+	}
+	if err == nil {
+		err = archive.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeExportJSON(archive *zip.Writer, name string, value interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func writeExportCSV(archive *zip.Writer, name string, rows [][]string) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// formatExportTime formats timestamps as RFC 3339 in UTC, with an empty string for unset values
+func formatExportTime(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
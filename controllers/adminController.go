@@ -0,0 +1,243 @@
+// controllers/adminController.go
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+	"github.com/mfuadfakhruzzaki/backend-api/wallet"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errOrderNotRefundable is returned when the order is not a paid package order
+var errOrderNotRefundable = errors.New("order is not refundable")
+
+// WalletAdjustmentRequest represents the request body for an admin balance adjustment
+type WalletAdjustmentRequest struct {
+	// Amount in whole rupiah; positive adds to and negative deducts from the balance
+	Amount int64  `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// RefundRequest represents the request body for refunding an order
+type RefundRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// AdminGetUserWallet returns the wallet balance of a user
+// @Summary Get the wallet balance of a user (admin)
+// @Tags Admin
+// @Produce  json
+// @Param   id  path  int  true  "User ID"
+// @Success 200 {object} SuccessResponse "Wallet balance"
+// @Failure 403 {object} ErrorResponse "Admin access required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/admin/users/{id}/wallet [get]
+func AdminGetUserWallet(c *gin.Context) {
+	if _, ok := currentAdmin(c); !ok {
+		return
+	}
+	target, ok := findUserByParam(c)
+	if !ok {
+		return
+	}
+	respondWalletBalance(c, target.ID)
+}
+
+// AdminGetUserWalletTransactions returns the wallet transaction history of a user
+// @Summary List wallet transactions of a user (admin)
+// @Tags Admin
+// @Produce  json
+// @Param   id         path   int  true   "User ID"
+// @Param   page       query  int  false  "Page number (default 1)"
+// @Param   page_size  query  int  false  "Items per page (default 20, max 100)"
+// @Success 200 {object} SuccessResponse{data=PaginatedResponse} "Wallet transactions"
+// @Failure 403 {object} ErrorResponse "Admin access required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/admin/users/{id}/wallet/transactions [get]
+func AdminGetUserWalletTransactions(c *gin.Context) {
+	if _, ok := currentAdmin(c); !ok {
+		return
+	}
+	target, ok := findUserByParam(c)
+	if !ok {
+		return
+	}
+	respondWalletHistory(c, target.ID)
+}
+
+// AdminAdjustWallet adds to or deducts from the wallet balance of a user
+// @Summary Adjust the wallet balance of a user (admin)
+// @Description Records an adjustment journal entry against the adjustments account. A deduction cannot make the balance negative.
+// @Tags Admin
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                      true  "User ID"
+// @Param   request  body  WalletAdjustmentRequest  true  "Signed amount and reason"
+// @Success 201 {object} SuccessResponse{data=models.JournalEntry} "Balance adjusted"
+// @Failure 400 {object} ErrorResponse "Invalid amount"
+// @Failure 402 {object} ErrorResponse "Insufficient wallet balance"
+// @Failure 403 {object} ErrorResponse "Admin access required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/admin/users/{id}/wallet/adjustments [post]
+func AdminAdjustWallet(c *gin.Context) {
+	var input WalletAdjustmentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	target, ok := findUserByParam(c)
+	if !ok {
+		return
+	}
+
+	var entry models.JournalEntry
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) (err error) {
+		entry, err = wallet.Adjust(tx, target.ID, money.Rupiah(input.Amount), strings.TrimSpace(input.Reason), admin.ID)
+		return err
+	})
+	metrics.WalletTransactionsTotal.WithLabelValues(models.JournalAdjustment, metrics.Result(err)).Inc()
+	switch {
+	case errors.Is(err, wallet.ErrInvalidAmount):
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidAdjustmentAmount)
+		return
+	case errors.Is(err, wallet.ErrInsufficientFunds):
+		respondError(c, http.StatusPaymentRequired, i18n.MsgInsufficientBalance)
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, i18n.MsgWalletTransactionFailed)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "Saldo wallet disesuaikan oleh admin",
+		"admin_id", admin.ID, "user_id", target.ID, "amount", input.Amount, "journal_id", entry.ID)
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: t(c, i18n.MsgWalletAdjusted),
+		Data:    entry,
+	})
+}
+
+// AdminRefundOrder refunds a paid package order to the wallet of its buyer
+// @Summary Refund an order (admin)
+// @Description Credits the order amount to the wallet balance of the buyer and marks the order as refunded. Orders fully covered by promo codes are only marked as refunded. If the line is still subscribed to the package of the order, the subscription is removed. Orders paid through the gateway are also refunded to the wallet.
+// @Tags Admin
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int            true  "Order ID"
+// @Param   request  body  RefundRequest  true  "Refund reason"
+// @Success 200 {object} SuccessResponse{data=models.Order} "Order refunded"
+// @Failure 400 {object} ErrorResponse "Invalid order ID"
+// @Failure 403 {object} ErrorResponse "Admin access required"
+// @Failure 404 {object} ErrorResponse "Order not found"
+// @Failure 409 {object} ErrorResponse "Order cannot be refunded"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/admin/orders/{id}/refund [post]
+func AdminRefundOrder(c *gin.Context) {
+	var input RefundRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidOrderID)
+		return
+	}
+
+	var order models.Order
+	err = config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// The order row is locked so that the same order cannot be refunded twice concurrently
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderID).First(&order).Error; err != nil {
+			return err
+		}
+		if order.Status != models.OrderPaid || order.Type != models.OrderTypePackage {
+			return errOrderNotRefundable
+		}
+
+		// Orders fully covered by promo codes were never paid, so there is nothing to credit to the wallet
+		if order.Amount > 0 {
+			_, err := wallet.Refund(tx, order.UserID, order.AmountMoney(), order.Gateway, order.OrderNumber, strings.TrimSpace(input.Reason), admin.ID)
+			metrics.WalletTransactionsTotal.WithLabelValues(models.JournalRefund, metrics.Result(err)).Inc()
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&order).Update("status", models.OrderRefunded).Error; err != nil {
+			return err
+		}
+		return deactivateOrder(tx, &order)
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		respondError(c, http.StatusNotFound, i18n.MsgOrderNotFound)
+		return
+	case errors.Is(err, errOrderNotRefundable), errors.Is(err, wallet.ErrDuplicateJournal), errors.Is(err, wallet.ErrInvalidAmount):
+		respondError(c, http.StatusConflict, i18n.MsgOrderNotRefundable)
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, i18n.MsgWalletTransactionFailed)
+		return
+	}
+	metrics.OrdersTotal.WithLabelValues(models.OrderRefunded).Inc()
+	slog.InfoContext(c.Request.Context(), "Order direfund ke wallet oleh admin", "admin_id", admin.ID, "order", order.OrderNumber)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgOrderRefunded),
+		Data:    order,
+	})
+}
+
+// deactivateOrder removes the subscription of a refunded order if the line is still subscribed to its package
+func deactivateOrder(tx *gorm.DB, order *models.Order) error {
+	if order.LineID == nil || order.PackageID == nil {
+		return nil
+	}
+	result := tx.Model(&models.Line{}).Where("id = ? AND user_id = ? AND package_id = ?", *order.LineID, order.UserID, *order.PackageID).
+		Update("package_id", nil)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return syncPrimaryLine(tx, order.UserID)
+}
+
+// findUserByParam loads the user selected by the :id path parameter
+func findUserByParam(c *gin.Context) (user models.User, ok bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidUserID)
+		return user, false
+	}
+	if err := config.DB.WithContext(c.Request.Context()).Where("id = ?", userID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return user, false
+	}
+	return user, true
+}
@@ -1,11 +1,17 @@
 package controllers
 
 import (
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
 	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
 	"github.com/mfuadfakhruzzaki/backend-api/utils"
 	"gorm.io/gorm"
 
@@ -18,6 +24,7 @@ type RegisterRequest struct {
 	Username    string `json:"username" binding:"required"`
 	Password    string `json:"password" binding:"required"`
 	PhoneNumber string `json:"phone_number"`
+	Language    string `json:"language" binding:"omitempty,oneof=id en"`
 }
 
 // VerificationRequest represents the structure of the email verification request body
@@ -40,7 +47,8 @@ type SuccessResponse struct {
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string `json:"error"`
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // Register handles user registration
@@ -52,63 +60,99 @@ type ErrorResponse struct {
 // @Param   user  body  RegisterRequest  true  "User registration data"
 // @Success 201 {object} SuccessResponse "Registration successful"
 // @Failure 400 {object} ErrorResponse "Invalid request payload or password is empty"
-// @Failure 409 {object} ErrorResponse "Email or username already exists"
+// @Failure 409 {object} ErrorResponse "Email, username or phone number already exists"
+// @Failure 429 {object} ErrorResponse "Too many requests"
 // @Failure 500 {object} ErrorResponse "Error creating user or sending verification email"
 // @Router  /auth/register [post]
 func Register(c *gin.Context) {
 	var userInput RegisterRequest
 	if err := c.ShouldBindJSON(&userInput); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request payload"})
+		respondBindingError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// Ensure password is not empty
 	if strings.TrimSpace(userInput.Password) == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Password cannot be empty"})
+		respondError(c, http.StatusBadRequest, i18n.MsgPasswordEmpty)
 		return
 	}
 
+	if !checkPasswordPolicy(c, userInput.Password, userInput.Email, userInput.Username) {
+		metrics.RegistrationsTotal.WithLabelValues("weak_password").Inc()
+		return
+	}
+
+	// Nomor telepon bersifat opsional, tetapi jika diisi harus valid dan belum dipakai
+	var phoneNumber, phoneOperator string
+	if strings.TrimSpace(userInput.PhoneNumber) != "" {
+		var ok bool
+		if phoneNumber, phoneOperator, ok = normalizePhoneInput(c, userInput.PhoneNumber, 0); !ok {
+			metrics.RegistrationsTotal.WithLabelValues("invalid_phone").Inc()
+			return
+		}
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(userInput.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Error hashing password"})
+		respondError(c, http.StatusInternalServerError, i18n.MsgHashPasswordFailed)
 		return
 	}
 
 	// Generate verification code
 	verificationCode, err := utils.GenerateVerificationCode()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Error generating verification code"})
+		respondError(c, http.StatusInternalServerError, i18n.MsgVerificationCodeFailed)
 		return
 	}
 
+	// Use the explicitly requested language, otherwise the one negotiated from Accept-Language
+	language := userInput.Language
+	if language == "" {
+		language = middleware.Language(c)
+	}
+
 	// Create new user with hashed password and verification code
 	user := models.User{
 		Email:            userInput.Email,
 		Username:         userInput.Username,
 		Password:         hashedPassword,
-		PhoneNumber:      userInput.PhoneNumber,
+		PhoneNumber:      phoneNumber,
+		PhoneOperator:    phoneOperator,
 		ProfilePicture:   "", // Initialize with empty string
 		PackageID:        nil,
 		EmailVerified:    false, // Email not verified yet
 		VerificationCode: verificationCode,
+		Language:         language,
 	}
 
-	result := config.DB.Create(&user)
-	if result.Error != nil {
+	// The phone number becomes the primary line of the new account
+	err = config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if phoneNumber == "" {
+			return nil
+		}
+		return tx.Create(&models.Line{UserID: user.ID, MSISDN: phoneNumber, Operator: phoneOperator, IsPrimary: true}).Error
+	})
+	if err != nil {
 		// Check for duplicate entry error (unique constraint violation)
-		if strings.Contains(result.Error.Error(), "duplicate key value") {
-			c.JSON(http.StatusConflict, ErrorResponse{Error: "Email or username already exists"})
+		if isDuplicateKeyError(err) {
+			metrics.RegistrationsTotal.WithLabelValues("conflict").Inc()
+			respondError(c, http.StatusConflict, i18n.MsgEmailOrUsernameExists)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Error creating user"})
+		metrics.RegistrationsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgCreateUserFailed)
 		return
 	}
+	metrics.RegistrationsTotal.WithLabelValues("success").Inc()
 
 	// Send verification email
-	if err := utils.SendVerificationEmail(user.Email, verificationCode); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to send verification email"})
+	if err := utils.SendVerificationEmail(c.Request.Context(), user.Email, verificationCode, user.Language); err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgSendVerificationFailed)
 		return
 	}
 
@@ -116,7 +160,7 @@ func Register(c *gin.Context) {
 	user.Password = ""
 
 	c.JSON(http.StatusCreated, SuccessResponse{
-		Message: "Registration successful! Please check your email to verify your account.",
+		Message: t(c, i18n.MsgRegistrationSuccess),
 		Data:    user,
 	})
 }
@@ -131,6 +175,7 @@ func Register(c *gin.Context) {
 // @Success 200 {object} SuccessResponse "Email verified successfully"
 // @Failure 400 {object} ErrorResponse "Invalid request payload or verification code"
 // @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
 // @Failure 500 {object} ErrorResponse "Failed to verify email"
 // @Router  /auth/verify-email [post]
 func VerifyEmail(c *gin.Context) {
@@ -138,87 +183,165 @@ func VerifyEmail(c *gin.Context) {
 
 	// Binding JSON input
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request payload"})
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Limit guessing of verification codes per account
+	if !guardAccount(c, "verify_email", input.Email, ratelimit.VerifyEmailPerAccount) {
+		metrics.EmailVerificationsTotal.WithLabelValues("rate_limited").Inc()
 		return
 	}
 
 	var user models.User
 	// Find user by email
-	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+	if err := config.DB.WithContext(c.Request.Context()).Where("email = ?", input.Email).First(&user).Error; err != nil {
+		respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
 		return
 	}
 
 	// Check if the verification code is correct
 	if user.VerificationCode != input.Code {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid verification code"})
+		metrics.EmailVerificationsTotal.WithLabelValues("invalid_code").Inc()
+		recordAccountFailure(c, "verify_email", input.Email, ratelimit.VerifyEmailLockout)
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidVerificationCode)
 		return
 	}
 
 	// Update user to set email as verified
 	user.EmailVerified = true
 	user.VerificationCode = "" // Optionally clear the verification code
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify email"})
+	if err := config.DB.WithContext(c.Request.Context()).Save(&user).Error; err != nil {
+		metrics.EmailVerificationsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgVerifyEmailFailed)
 		return
 	}
+	metrics.EmailVerificationsTotal.WithLabelValues("success").Inc()
+	resetAccountFailures(c, "verify_email", input.Email)
 
 	c.JSON(http.StatusOK, SuccessResponse{
-		Message: "Email verified successfully!",
+		Message: t(c, i18n.MsgEmailVerified),
 	})
 }
 
 // Login handles user authentication
 // @Summary User login
-// @Description This endpoint allows users to log in by providing email and password. A JWT token will be returned upon successful login.
+// @Description This endpoint allows users to log in by providing email and password. A JWT token will be returned upon successful login. If two-factor authentication is enabled, a challenge token is returned instead and the login must be completed via /auth/login/2fa.
 // @Tags Auth
 // @Accept  json
 // @Produce  json
 // @Param   credentials  body  LoginCredentials  true  "User credentials (email and password)"
-// @Success 200 {object} SuccessResponse "JWT token"
+// @Success 200 {object} SuccessResponse "JWT token, or challenge token when 2FA is enabled"
 // @Failure 400 {object} ErrorResponse "Invalid request payload"
 // @Failure 401 {object} ErrorResponse "Unauthorized, invalid credentials or email not verified"
-// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
 // @Failure 500 {object} ErrorResponse "Error generating token or database error"
 // @Router  /auth/login [post]
 func Login(c *gin.Context) {
 	var credentials LoginCredentials
 
 	if err := c.ShouldBindJSON(&credentials); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request payload"})
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Reject early when the account is locked or has exceeded its attempt budget
+	if !guardAccount(c, "login", credentials.Email, ratelimit.LoginPerAccount) {
+		metrics.LoginsTotal.WithLabelValues("rate_limited").Inc()
 		return
 	}
 
 	var user models.User
-	result := config.DB.Where("email = ?", credentials.Email).First(&user)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Database error"})
-		}
+	result := config.DB.WithContext(c.Request.Context()).Where("email = ?", credentials.Email).First(&user)
+	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
 		return
 	}
 
+	// Unknown emails and wrong passwords get the same response and comparable timing,
+	// so the endpoint cannot be used to enumerate registered accounts
+	if result.Error == gorm.ErrRecordNotFound {
+		utils.CheckDummyPassword(credentials.Password)
+	}
+	if result.Error == gorm.ErrRecordNotFound || !utils.CheckPasswordHash(credentials.Password, user.Password) {
+		metrics.LoginsTotal.WithLabelValues("invalid_credentials").Inc()
+		recordAccountFailure(c, "login", credentials.Email, ratelimit.LoginLockout)
+		respondError(c, http.StatusUnauthorized, i18n.MsgInvalidCredentials)
+		return
+	}
+	resetAccountFailures(c, "login", credentials.Email)
+	upgradePasswordHash(c, &user, credentials.Password)
+
 	// Check if email is verified
 	if !user.EmailVerified {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Email not verified. Please verify your email first."})
+		metrics.LoginsTotal.WithLabelValues("email_not_verified").Inc()
+		respondError(c, http.StatusUnauthorized, i18n.MsgEmailNotVerified)
+		return
+	}
+
+	finishLogin(c, &user)
+}
+
+// upgradePasswordHash rehashes a verified password when it was hashed with an older algorithm or
+// parameters (e.g. bcrypt). Failures are only logged because the login itself has succeeded.
+func upgradePasswordHash(c *gin.Context, user *models.User, password string) {
+	if !utils.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err == nil {
+		err = config.DB.WithContext(c.Request.Context()).Model(user).Update("password", hashedPassword).Error
+	}
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "Gagal memperbarui hash password", "user_id", user.ID, "error", err)
 		return
 	}
+	metrics.PasswordRehashesTotal.Inc()
+}
+
+// finishLogin continues a login whose first factor (password or identity provider) has been verified.
+// Accounts with 2FA receive a short-lived challenge instead of an access token.
+func finishLogin(c *gin.Context, user *models.User) {
+	if user.TwoFactorEnabled {
+		challengeToken, err := utils.GeneratePurposeToken(user.Email, utils.Purpose2FA, config.GetEnvDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute))
+		if err != nil {
+			metrics.LoginsTotal.WithLabelValues("failure").Inc()
+			respondError(c, http.StatusInternalServerError, i18n.MsgGenerateTokenFailed)
+			return
+		}
+		metrics.LoginsTotal.WithLabelValues("two_factor_required").Inc()
 
-	if !utils.CheckPasswordHash(credentials.Password, user.Password) {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid password"})
+		c.JSON(http.StatusOK, SuccessResponse{
+			Message: t(c, i18n.MsgTwoFactorRequired),
+			Data:    gin.H{"two_factor_required": true, "challenge_token": challengeToken},
+		})
+		return
+	}
+
+	completeLogin(c, user)
+}
+
+// completeLogin issues the access token once every authentication factor has been verified
+func completeLogin(c *gin.Context, user *models.User) {
+	session, err := createSession(c, user)
+	if err != nil {
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgCreateSessionFailed)
 		return
 	}
 
-	tokenString, err := utils.GenerateJWT(user.Email)
+	tokenString, err := utils.GenerateJWT(user.Email, session.TokenID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Error generating token"})
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgGenerateTokenFailed)
 		return
 	}
+	metrics.LoginsTotal.WithLabelValues("success").Inc()
 
 	c.JSON(http.StatusOK, SuccessResponse{
-		Message: "Login successful",
+		Message: t(c, i18n.MsgLoginSuccess),
 		Data:    gin.H{"token": tokenString},
 	})
 }
@@ -0,0 +1,54 @@
+// controllers/currentUser.go
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"gorm.io/gorm"
+)
+
+// currentUser memuat pengguna yang sedang login berdasarkan email dari middleware JWT.
+// Jika gagal, respons error sudah dikirim dan ok bernilai false.
+func currentUser(c *gin.Context) (user models.User, ok bool) {
+	email, exists := c.Get(string(middleware.UserContextKey))
+	if !exists {
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailMissing)
+		return user, false
+	}
+
+	emailStr, valid := email.(string)
+	if !valid || emailStr == "" {
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailInvalid)
+		return user, false
+	}
+
+	result := config.DB.WithContext(c.Request.Context()).Where("email = ?", emailStr).First(&user)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return user, false
+	}
+	return user, true
+}
+
+// currentAdmin memuat pengguna yang sedang login dan memastikan perannya admin.
+// Jika bukan admin, respons 403 sudah dikirim dan ok bernilai false.
+func currentAdmin(c *gin.Context) (user models.User, ok bool) {
+	user, ok = currentUser(c)
+	if !ok {
+		return user, false
+	}
+	if user.Role != models.RoleAdmin {
+		respondError(c, http.StatusForbidden, i18n.MsgAdminRequired)
+		return user, false
+	}
+	return user, true
+}
@@ -0,0 +1,345 @@
+// controllers/emailChangeController.go
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"gorm.io/gorm"
+)
+
+// errEmailChangeConsumed is returned when a concurrent request already confirmed or undid the change
+var errEmailChangeConsumed = errors.New("email change already processed")
+
+// EmailChangeRequest represents the request body for starting an email change
+type EmailChangeRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	// Password may be omitted only by accounts that have no password (e.g. created with Google)
+	Password string `json:"password"`
+}
+
+// EmailChangeConfirmRequest represents the request body for confirming the new email
+type EmailChangeConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// EmailChangeUndoRequest represents the request body for undoing an email change from the old address
+type EmailChangeUndoRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// RequestEmailChange starts an email change by sending a confirmation code to the new address
+// @Summary Start an email change
+// @Description Sends a confirmation code to the new address after confirming the password. The account keeps its current email until the code is confirmed with /api/users/email/confirm.
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param   request  body  EmailChangeRequest  true  "New email and current password"
+// @Success 202 {object} SuccessResponse "Confirmation code sent to the new address"
+// @Failure 400 {object} ErrorResponse "Invalid email or email already used"
+// @Failure 401 {object} ErrorResponse "Wrong password"
+// @Failure 429 {object} ErrorResponse "Too many requests"
+// @Failure 500 {object} ErrorResponse "Failed to start the email change"
+// @Router  /api/users/email [post]
+func RequestEmailChange(c *gin.Context) {
+	var input EmailChangeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !guardAccount(c, "email_change_request", user.Email, ratelimit.EmailChangeRequestPerAccount) {
+		return
+	}
+
+	newEmail := accountKey(input.NewEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		respondError(c, http.StatusBadRequest, i18n.MsgEmailUnchanged)
+		return
+	}
+
+	// Password confirmation shares the login attempt budget and lockout
+	if user.Password != "" {
+		if !guardAccount(c, "login", user.Email, ratelimit.LoginPerAccount) {
+			return
+		}
+		if !utils.CheckPasswordHash(input.Password, user.Password) {
+			recordAccountFailure(c, "login", user.Email, ratelimit.LoginLockout)
+			metrics.EmailChangesTotal.WithLabelValues("request", "invalid_password").Inc()
+			respondError(c, http.StatusUnauthorized, i18n.MsgInvalidPassword)
+			return
+		}
+		resetAccountFailures(c, "login", user.Email)
+	}
+
+	ctx := c.Request.Context()
+	db := config.DB.WithContext(ctx)
+
+	var taken int64
+	if err := db.Model(&models.User{}).Where("LOWER(email) = ?", newEmail).Count(&taken).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	if taken > 0 {
+		metrics.EmailChangesTotal.WithLabelValues("request", "conflict").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgEmailTaken)
+		return
+	}
+
+	code, err := createEmailChange(db, &user, newEmail)
+	if err != nil {
+		metrics.EmailChangesTotal.WithLabelValues("request", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgEmailChangeFailed)
+		return
+	}
+
+	if err := utils.SendEmailChangeCodeEmail(ctx, newEmail, code, emailChangeCodeTTL(), user.Language); err != nil {
+		metrics.EmailChangesTotal.WithLabelValues("request", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgSendVerificationFailed)
+		return
+	}
+	metrics.EmailChangesTotal.WithLabelValues("request", "success").Inc()
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: t(c, i18n.MsgEmailChangeRequested, newEmail),
+	})
+}
+
+// ConfirmEmailChange switches the account to the new email after the confirmation code is verified
+// @Summary Confirm an email change
+// @Description Verifies the code sent to the new address and switches the account email. The old address receives a notice with a link to undo the change. Existing sessions stay valid.
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param   request  body  EmailChangeConfirmRequest  true  "Confirmation code"
+// @Success 200 {object} SuccessResponse "Email changed"
+// @Failure 400 {object} ErrorResponse "Invalid or expired code, or email already used"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/email/confirm [post]
+func ConfirmEmailChange(c *gin.Context) {
+	var input EmailChangeConfirmRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !guardAccount(c, "email_change", user.Email, ratelimit.EmailChangeConfirmPerAccount) {
+		return
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+
+	var change models.EmailChange
+	err := db.Where("user_id = ? AND confirmed_at IS NULL AND expires_at > ? AND attempts < ?", user.ID, time.Now(), maxLoginCodeAttempts).
+		Order("created_at DESC").First(&change).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	expected := utils.HashToken(strings.ToUpper(strings.TrimSpace(input.Code)))
+	if err != nil || subtle.ConstantTimeCompare([]byte(expected), []byte(change.CodeHash)) != 1 {
+		if err == nil {
+			db.Model(&change).UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+		}
+		recordAccountFailure(c, "email_change", user.Email, ratelimit.VerifyEmailLockout)
+		metrics.EmailChangesTotal.WithLabelValues("confirm", "invalid_code").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidEmailChangeCode)
+		return
+	}
+
+	undoToken, err := utils.RandomToken(32)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgEmailChangeFailed)
+		return
+	}
+	undoExpiresAt := time.Now().Add(emailChangeUndoTTL())
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.EmailChange{}).Where("id = ? AND confirmed_at IS NULL", change.ID).Updates(map[string]interface{}{
+			"confirmed_at":    now,
+			"undo_token_hash": utils.HashToken(undoToken),
+			"undo_expires_at": undoExpiresAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errEmailChangeConsumed
+		}
+		// Receiving the code proves ownership of the new address
+		return tx.Model(&user).Updates(map[string]interface{}{"email": change.NewEmail, "email_verified": true, "verification_code": ""}).Error
+	})
+	switch {
+	case errors.Is(err, errEmailChangeConsumed):
+		metrics.EmailChangesTotal.WithLabelValues("confirm", "invalid_code").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidEmailChangeCode)
+		return
+	case isDuplicateKeyError(err):
+		metrics.EmailChangesTotal.WithLabelValues("confirm", "conflict").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgEmailTaken)
+		return
+	case err != nil:
+		metrics.EmailChangesTotal.WithLabelValues("confirm", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	resetAccountFailures(c, "email_change", change.OldEmail)
+	metrics.EmailChangesTotal.WithLabelValues("confirm", "success").Inc()
+
+	notifyEmailChanged(c, &user, &change, undoToken)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgEmailChanged),
+		Data:    gin.H{"email": change.NewEmail},
+	})
+}
+
+// UndoEmailChange restores the previous email using the token sent to the old address
+// @Summary Undo an email change
+// @Description Restores the previous email with the token from the "your email was changed" notice and ends every session of the account, in case the change was made by someone else.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param   request  body  EmailChangeUndoRequest  true  "Undo token"
+// @Success 200 {object} SuccessResponse "Email change undone"
+// @Failure 400 {object} ErrorResponse "Invalid or expired undo token"
+// @Failure 409 {object} ErrorResponse "The previous email is now used by another account"
+// @Failure 429 {object} ErrorResponse "Too many requests"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /auth/email/undo [post]
+func UndoEmailChange(c *gin.Context) {
+	var input EmailChangeUndoRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+
+	var change models.EmailChange
+	err := db.Where("undo_token_hash = ? AND confirmed_at IS NOT NULL AND undone_at IS NULL AND undo_expires_at > ?", utils.HashToken(input.Token), time.Now()).
+		First(&change).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			metrics.EmailChangesTotal.WithLabelValues("undo", "invalid_token").Inc()
+			respondError(c, http.StatusBadRequest, i18n.MsgInvalidUndoToken)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.EmailChange{}).Where("id = ? AND undone_at IS NULL", change.ID).Update("undone_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errEmailChangeConsumed
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", change.UserID).
+			Updates(map[string]interface{}{"email": change.OldEmail, "email_verified": true}).Error; err != nil {
+			return err
+		}
+		// Cancel any further change the other party may have started
+		if err := tx.Where("user_id = ? AND confirmed_at IS NULL", change.UserID).Delete(&models.EmailChange{}).Error; err != nil {
+			return err
+		}
+		_, err := revokeSessions(tx, change.UserID, 0)
+		return err
+	})
+	switch {
+	case errors.Is(err, errEmailChangeConsumed):
+		metrics.EmailChangesTotal.WithLabelValues("undo", "invalid_token").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidUndoToken)
+		return
+	case isDuplicateKeyError(err):
+		metrics.EmailChangesTotal.WithLabelValues("undo", "conflict").Inc()
+		respondError(c, http.StatusConflict, i18n.MsgEmailTaken)
+		return
+	case err != nil:
+		metrics.EmailChangesTotal.WithLabelValues("undo", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	metrics.EmailChangesTotal.WithLabelValues("undo", "success").Inc()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgEmailChangeUndone),
+	})
+}
+
+// createEmailChange replaces any unconfirmed change of the user with a new one and returns its code
+func createEmailChange(db *gorm.DB, user *models.User, newEmail string) (string, error) {
+	code, err := utils.GenerateVerificationCode()
+	if err != nil {
+		return "", err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND confirmed_at IS NULL", user.ID).Delete(&models.EmailChange{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.EmailChange{
+			UserID:    user.ID,
+			OldEmail:  user.Email,
+			NewEmail:  newEmail,
+			CodeHash:  utils.HashToken(code),
+			ExpiresAt: time.Now().Add(emailChangeCodeTTL()),
+		}).Error
+	})
+	return code, err
+}
+
+// notifyEmailChanged emails the old address about the change, with a way to undo it, in the background
+func notifyEmailChanged(c *gin.Context, user *models.User, change *models.EmailChange, undoToken string) {
+	// Without a client page the token itself is sent so it can still be submitted to /auth/email/undo
+	undo := clientLink(config.GetEnv("EMAIL_CHANGE_UNDO_URL", ""), undoToken)
+	if undo == "" {
+		undo = undoToken
+	}
+
+	userID, language := user.ID, user.Language
+	oldEmail, newEmail, validFor := change.OldEmail, change.NewEmail, emailChangeUndoTTL()
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	services.StartWorker("email-changed-notice", func(context.Context) {
+		if err := utils.SendEmailChangedNotice(ctx, oldEmail, newEmail, undo, validFor, language); err != nil {
+			slog.ErrorContext(ctx, "Gagal mengirim pemberitahuan penggantian email", "user_id", userID, "error", err)
+		}
+	})
+}
+
+func emailChangeCodeTTL() time.Duration {
+	return config.GetEnvDuration("EMAIL_CHANGE_CODE_TTL", 30*time.Minute)
+}
+
+func emailChangeUndoTTL() time.Duration {
+	return config.GetEnvDuration("EMAIL_CHANGE_UNDO_TTL", 72*time.Hour)
+}
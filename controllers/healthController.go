@@ -0,0 +1,90 @@
+// controllers/healthController.go
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+)
+
+// readinessTimeout membatasi durasi setiap pemeriksaan readiness
+const readinessTimeout = 3 * time.Second
+
+// Healthz godoc
+// @Summary      Liveness probe
+// @Description  Returns 200 as long as the process is running and able to serve HTTP
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} map[string]string
+// @Router       /healthz [get]
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz godoc
+// @Summary      Readiness probe
+// @Description  Checks the database connection, storage backend and migration state. Returns 503 while shutting down.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} map[string]interface{}
+// @Failure      503  {object} map[string]interface{}
+// @Router       /readyz [get]
+func Readyz(c *gin.Context) {
+	if services.IsShuttingDown() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "shutting_down",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := gin.H{}
+	ready := true
+	record := func(name string, err error) {
+		if err != nil {
+			checks[name] = err.Error()
+			ready = false
+			return
+		}
+		checks[name] = "ok"
+	}
+
+	record("database", services.CheckDatabase(ctx))
+	record("migrations", services.CheckMigrations())
+	record("storage", services.CheckStorage(ctx))
+
+	status := http.StatusOK
+	state := "ready"
+	if !ready {
+		status = http.StatusServiceUnavailable
+		state = "not_ready"
+	}
+
+	c.JSON(status, gin.H{
+		"status": state,
+		"checks": checks,
+	})
+}
+
+// Version godoc
+// @Summary      Build information
+// @Description  Returns the version, git commit and build time injected at build time
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} map[string]string
+// @Router       /version [get]
+func Version(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version":    config.Version,
+		"git_commit": config.GitCommit,
+		"build_time": config.BuildTime,
+		"go_version": runtime.Version(),
+	})
+}
@@ -0,0 +1,88 @@
+// controllers/invoiceController.go
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/invoice"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"gorm.io/gorm"
+)
+
+// GetInvoices returns the invoices of the authenticated user, newest first
+// @Summary List invoices
+// @Description Returns the invoices issued for paid orders, with the tax breakdown. Download the PDF from /api/invoices/{id}.
+// @Tags Invoices
+// @Produce  json
+// @Success 200 {object} SuccessResponse{data=[]models.Invoice} "Invoices"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/invoices [get]
+func GetInvoices(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var invoices []models.Invoice
+	if err := config.DB.WithContext(c.Request.Context()).
+		Where("user_id = ?", user.ID).Order("issued_at DESC, id DESC").Find(&invoices).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgInvoicesFetched),
+		Data:    invoices,
+	})
+}
+
+// GetInvoice downloads an invoice of the authenticated user as PDF
+// @Summary Download an invoice
+// @Description Renders the invoice as PDF on the server, with labels in the language of the user.
+// @Tags Invoices
+// @Produce  application/pdf
+// @Param   id  path  int  true  "Invoice ID"
+// @Success 200 {file} file "Invoice PDF"
+// @Failure 400 {object} ErrorResponse "Invalid invoice ID"
+// @Failure 404 {object} ErrorResponse "Invoice not found"
+// @Failure 500 {object} ErrorResponse "Database error or PDF generation failed"
+// @Router  /api/invoices/{id} [get]
+func GetInvoice(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidInvoiceID)
+		return
+	}
+
+	var inv models.Invoice
+	err = config.DB.WithContext(c.Request.Context()).Where("id = ? AND user_id = ?", invoiceID, user.ID).First(&inv).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, i18n.MsgInvoiceNotFound)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return
+	}
+
+	pdf, err := invoice.RenderPDF(c.Request.Context(), &inv, middleware.Language(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgInvoiceRenderFailed)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, invoice.FileName(&inv)))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
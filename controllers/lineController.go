@@ -0,0 +1,381 @@
+// controllers/lineController.go
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"gorm.io/gorm"
+)
+
+// errPrimaryLineInUse is returned when the primary line is removed while the user still has other lines
+var errPrimaryLineInUse = errors.New("primary line still has other lines")
+
+// AddLineRequest represents the request body for adding a phone line
+type AddLineRequest struct {
+	MSISDN string `json:"msisdn" binding:"required"`
+	Label  string `json:"label" binding:"max=50"`
+}
+
+// UpdateLineRequest represents the request body for renaming a phone line
+type UpdateLineRequest struct {
+	Label string `json:"label" binding:"max=50"`
+}
+
+// GetLines returns the phone lines of the authenticated user
+// @Summary List phone lines
+// @Description Returns every phone line of the user with its subscribed package. The primary line is mirrored to phone_number and package_id in the profile.
+// @Tags Lines
+// @Produce  json
+// @Success 200 {object} SuccessResponse{data=[]models.Line} "Phone lines"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/lines [get]
+func GetLines(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var lines []models.Line
+	if err := config.DB.WithContext(c.Request.Context()).Preload("Package").
+		Where("user_id = ?", user.ID).Order("is_primary DESC, created_at").Find(&lines).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgLinesFetched),
+		Data:    lines,
+	})
+}
+
+// AddLine adds a phone line to the authenticated user
+// @Summary Add a phone line
+// @Description Adds a phone line (e.g. a tablet or a family member's SIM). The number is normalized to E.164 and must be verified by SMS before a package can be selected for it. The first line becomes the primary line.
+// @Tags Lines
+// @Accept  json
+// @Produce  json
+// @Param   request  body  AddLineRequest  true  "Phone number and label"
+// @Success 201 {object} SuccessResponse{data=models.Line} "Line added"
+// @Failure 400 {object} ErrorResponse "Invalid phone number or too many lines"
+// @Failure 409 {object} ErrorResponse "Phone number already used"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/lines [post]
+func AddLine(c *gin.Context) {
+	var input AddLineRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	phone, operator, ok := normalizePhoneInput(c, input.MSISDN, user.ID)
+	if !ok {
+		return
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+	var count int64
+	if err := db.Model(&models.Line{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	maxLines := config.GetEnvInt("LINES_MAX_PER_USER", 5)
+	if count >= int64(maxLines) {
+		respondError(c, http.StatusBadRequest, i18n.MsgLineLimitReached, maxLines)
+		return
+	}
+
+	line := models.Line{
+		UserID:    user.ID,
+		MSISDN:    phone,
+		Label:     strings.TrimSpace(input.Label),
+		Operator:  operator,
+		IsPrimary: count == 0,
+	}
+	if line.IsPrimary {
+		// The subscription chosen before the user had any line moves to the new primary line
+		line.PackageID = user.PackageID
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&line).Error; err != nil {
+			return err
+		}
+		if line.IsPrimary {
+			return syncPrimaryLine(tx, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			respondError(c, http.StatusConflict, i18n.MsgPhoneNumberTaken)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: t(c, i18n.MsgLineAdded),
+		Data:    line,
+	})
+}
+
+// UpdateLine renames a phone line
+// @Summary Rename a phone line
+// @Tags Lines
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                true  "Line ID"
+// @Param   request  body  UpdateLineRequest  true  "New label"
+// @Success 200 {object} SuccessResponse{data=models.Line} "Line updated"
+// @Failure 404 {object} ErrorResponse "Line not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/lines/{id} [put]
+func UpdateLine(c *gin.Context) {
+	var input UpdateLineRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	line, ok := findLine(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := config.DB.WithContext(c.Request.Context()).Model(&line).Update("label", strings.TrimSpace(input.Label)).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgLineUpdated),
+		Data:    line,
+	})
+}
+
+// DeleteLine removes a phone line together with its subscription
+// @Summary Remove a phone line
+// @Description Removes a line and its subscription. The primary line can only be removed when it is the last line; otherwise make another line primary first.
+// @Tags Lines
+// @Produce  json
+// @Param   id  path  int  true  "Line ID"
+// @Success 200 {object} SuccessResponse "Line removed"
+// @Failure 400 {object} ErrorResponse "The primary line cannot be removed while other lines exist"
+// @Failure 404 {object} ErrorResponse "Line not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/lines/{id} [delete]
+func DeleteLine(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	line, ok := findLine(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		return deleteLine(tx, &line)
+	})
+	switch {
+	case errors.Is(err, errPrimaryLineInUse):
+		respondError(c, http.StatusBadRequest, i18n.MsgPrimaryLineInUse)
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgLineDeleted),
+	})
+}
+
+// SetPrimaryLine makes a line the primary line of the user
+// @Summary Set the primary line
+// @Description The primary line is the default line for endpoints that accept line_id, and is mirrored to phone_number, phone_verified and package_id in the profile.
+// @Tags Lines
+// @Produce  json
+// @Param   id  path  int  true  "Line ID"
+// @Success 200 {object} SuccessResponse{data=models.Line} "Primary line changed"
+// @Failure 404 {object} ErrorResponse "Line not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/lines/{id}/primary [post]
+func SetPrimaryLine(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	line, ok := findLine(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// The old primary line is cleared first so that the partial unique index is never violated
+		if err := tx.Model(&models.Line{}).Where("user_id = ? AND is_primary", user.ID).Update("is_primary", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&line).Update("is_primary", true).Error; err != nil {
+			return err
+		}
+		return syncPrimaryLine(tx, user.ID)
+	})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgPrimaryLineChanged),
+		Data:    line,
+	})
+}
+
+// findLine loads a line of the user by its ID. The error response is already sent when ok is false.
+func findLine(c *gin.Context, userID uint, id string) (line models.Line, ok bool) {
+	lineID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidLineID)
+		return line, false
+	}
+
+	err = config.DB.WithContext(c.Request.Context()).Where("id = ? AND user_id = ?", lineID, userID).First(&line).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, i18n.MsgLineNotFound)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return line, false
+	}
+	return line, true
+}
+
+// lineFromQuery returns the line selected with ?line_id=, or the primary line when the parameter is absent.
+// line is nil when the user has no line yet. The error response is already sent when ok is false.
+func lineFromQuery(c *gin.Context, user *models.User) (*models.Line, bool) {
+	if id := c.Query("line_id"); id != "" {
+		line, ok := findLine(c, user.ID, id)
+		if !ok {
+			return nil, false
+		}
+		return &line, true
+	}
+
+	line, found, err := primaryLine(config.DB.WithContext(c.Request.Context()), user.ID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return nil, false
+	}
+	if !found {
+		return nil, true
+	}
+	return &line, true
+}
+
+// primaryLine loads the primary line of the user, if any
+func primaryLine(db *gorm.DB, userID uint) (models.Line, bool, error) {
+	var line models.Line
+	err := db.Where("user_id = ? AND is_primary", userID).First(&line).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return line, false, nil
+	}
+	return line, err == nil, err
+}
+
+// setPrimaryPhone changes the number of the primary line, creating it when the user has none, and
+// resets its verification when the number changes. An empty phone removes the primary line.
+func setPrimaryPhone(tx *gorm.DB, user *models.User, phone, operator string) error {
+	line, found, err := primaryLine(tx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case phone == "" && found:
+		return deleteLine(tx, &line)
+	case phone == "":
+		return nil
+	case !found:
+		err = tx.Create(&models.Line{
+			UserID:    user.ID,
+			MSISDN:    phone,
+			Operator:  operator,
+			IsPrimary: true,
+			PackageID: user.PackageID,
+		}).Error
+	case line.MSISDN != phone:
+		err = tx.Model(&line).Updates(map[string]interface{}{"msisdn": phone, "operator": operator, "verified": false}).Error
+	}
+	if err != nil {
+		return err
+	}
+	return syncPrimaryLine(tx, user.ID)
+}
+
+// deleteLine removes a line and its pending verification codes. The primary line can only be removed
+// when it is the last line of the user.
+func deleteLine(tx *gorm.DB, line *models.Line) error {
+	wasPrimary := line.IsPrimary
+	if wasPrimary {
+		var others int64
+		if err := tx.Model(&models.Line{}).Where("user_id = ? AND id <> ?", line.UserID, line.ID).Count(&others).Error; err != nil {
+			return err
+		}
+		if others > 0 {
+			return errPrimaryLineInUse
+		}
+	}
+
+	if err := tx.Where("line_id = ?", line.ID).Delete(&models.PhoneVerification{}).Error; err != nil {
+		return err
+	}
+	// The primary flag is cleared so that a new primary line can be created later
+	if err := tx.Model(line).Update("is_primary", false).Error; err != nil {
+		return err
+	}
+	if err := tx.Delete(line).Error; err != nil {
+		return err
+	}
+	if wasPrimary {
+		return syncPrimaryLine(tx, line.UserID)
+	}
+	return nil
+}
+
+// syncPrimaryLine copies the primary line to the phone and subscription fields of the user, which are
+// kept for clients that predate multiple lines. Without a primary line the package is left untouched.
+func syncPrimaryLine(tx *gorm.DB, userID uint) error {
+	line, found, err := primaryLine(tx, userID)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{"phone_number": "", "phone_operator": "", "phone_verified": false}
+	if found {
+		updates = map[string]interface{}{
+			"phone_number":   line.MSISDN,
+			"phone_operator": line.Operator,
+			"phone_verified": line.Verified,
+			"package_id":     line.PackageID,
+		}
+	}
+	return tx.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error
+}
@@ -0,0 +1,456 @@
+// controllers/oidcController.go
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const (
+	// oidcStateCookie holds the sealed state, nonce and PKCE verifier between login and callback
+	oidcStateCookie = "oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+	oidcLinkTTL     = 10 * time.Minute
+)
+
+// usernameSanitizer strips characters that are not allowed in generated usernames
+var usernameSanitizer = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// oidcLoginState is sealed into the state cookie so the callback can be validated without server-side storage
+type oidcLoginState struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// oidcPendingLink is sealed into the link token returned when the provider email matches an existing account
+type oidcPendingLink struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	Email    string `json:"email"`
+}
+
+// OIDCLinkRequest represents the request body for linking a provider identity to an existing account
+type OIDCLinkRequest struct {
+	LinkToken string `json:"link_token" binding:"required"`
+	Password  string `json:"password" binding:"required"`
+}
+
+// OIDCLogin starts the authorization-code flow with PKCE for the given provider
+// @Summary Sign in with an OpenID Connect provider
+// @Description Redirects to the provider (e.g. Google) using the authorization-code flow with PKCE. The state is kept in a short-lived HttpOnly cookie.
+// @Tags Auth
+// @Param   provider  path  string  true  "Provider name, e.g. google"
+// @Success 302 "Redirect to the provider"
+// @Failure 404 {object} ErrorResponse "Unknown sign-in provider"
+// @Failure 502 {object} ErrorResponse "Provider is unavailable"
+// @Router  /auth/oidc/{provider}/login [get]
+func OIDCLogin(c *gin.Context) {
+	provider, ok := oidcProvider(c)
+	if !ok {
+		return
+	}
+
+	state, errState := utils.RandomToken(32)
+	nonce, errNonce := utils.RandomToken(32)
+	if errState != nil || errNonce != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgOIDCAuthFailed)
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	sealed, err := utils.SealToken(oidcLoginState{
+		Provider: provider.Name,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+	}, oidcStateTTL)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgOIDCAuthFailed)
+		return
+	}
+
+	setOIDCStateCookie(c, sealed, int(oidcStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, provider.AuthCodeURL(state, nonce, verifier))
+}
+
+// OIDCCallback completes the authorization-code flow and signs the user in
+// @Summary OpenID Connect callback
+// @Description Exchanges the authorization code, verifies the ID token and signs the user in. New users are registered automatically with a verified email. If an account with the same email already exists, a link token is returned which must be confirmed with the account password via /auth/oidc/link.
+// @Tags Auth
+// @Produce  json
+// @Param   provider  path   string  true  "Provider name, e.g. google"
+// @Param   code      query  string  true  "Authorization code"
+// @Param   state     query  string  true  "State"
+// @Success 200 {object} SuccessResponse "JWT token, or challenge token when 2FA is enabled"
+// @Failure 400 {object} ErrorResponse "Invalid or expired sign-in session"
+// @Failure 401 {object} ErrorResponse "Sign-in with the provider failed"
+// @Failure 403 {object} ErrorResponse "Provider email is not verified"
+// @Failure 409 {object} ErrorResponse "Account exists, password confirmation required"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /auth/oidc/{provider}/callback [get]
+func OIDCCallback(c *gin.Context) {
+	provider, ok := oidcProvider(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	// The state cookie is single-use regardless of the outcome
+	cookie, cookieErr := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+
+	var state oidcLoginState
+	if cookieErr != nil || utils.OpenToken(cookie, &state) != nil || state.Provider != provider.Name ||
+		subtle.ConstantTimeCompare([]byte(state.State), []byte(c.Query("state"))) != 1 {
+		metrics.OIDCLoginsTotal.WithLabelValues(provider.Name, "invalid_state").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgOIDCInvalidState)
+		return
+	}
+
+	if providerErr := c.Query("error"); providerErr != "" || c.Query("code") == "" {
+		metrics.OIDCLoginsTotal.WithLabelValues(provider.Name, "denied").Inc()
+		respondError(c, http.StatusUnauthorized, i18n.MsgOIDCAuthFailed)
+		return
+	}
+
+	identity, err := provider.Exchange(ctx, c.Query("code"), state.Verifier, state.Nonce)
+	if err != nil {
+		slog.WarnContext(ctx, "Gagal menukar authorization code OIDC", "provider", provider.Name, "error", err)
+		metrics.OIDCLoginsTotal.WithLabelValues(provider.Name, "failure").Inc()
+		respondError(c, http.StatusUnauthorized, i18n.MsgOIDCAuthFailed)
+		return
+	}
+	if identity.Email == "" || !identity.EmailVerified {
+		metrics.OIDCLoginsTotal.WithLabelValues(provider.Name, "email_not_verified").Inc()
+		respondError(c, http.StatusForbidden, i18n.MsgOIDCEmailNotVerified)
+		return
+	}
+
+	db := config.DB.WithContext(ctx)
+
+	// Returning user: the provider identity is already linked
+	var linked models.UserIdentity
+	err = db.Where("provider = ? AND subject = ?", provider.Name, identity.Subject).First(&linked).Error
+	if err == nil {
+		var user models.User
+		err = db.Where("id = ?", linked.UserID).First(&user).Error
+		if err == nil {
+			metrics.OIDCLoginsTotal.WithLabelValues(provider.Name, "success").Inc()
+			finishLogin(c, &user)
+			return
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+			return
+		}
+		// Identity left over from a deleted account: drop it so the provider account can sign up again
+		if err := db.Delete(&linked).Error; err != nil {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+			return
+		}
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	// Existing account with the same email: require the password before linking,
+	// so an attacker controlling a provider account cannot take over the user
+	var existing models.User
+	err = db.Where("LOWER(email) = ?", identity.Email).First(&existing).Error
+	if err == nil {
+		linkToken, err := utils.SealToken(oidcPendingLink{
+			Provider: provider.Name,
+			Subject:  identity.Subject,
+			Email:    existing.Email,
+		}, oidcLinkTTL)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, i18n.MsgOIDCAuthFailed)
+			return
+		}
+		metrics.OIDCLoginsTotal.WithLabelValues(provider.Name, "link_required").Inc()
+		c.JSON(http.StatusConflict, SuccessResponse{
+			Message: t(c, i18n.MsgOIDCLinkRequired),
+			Data:    gin.H{"link_required": true, "link_token": linkToken, "email": existing.Email},
+		})
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	// New user: register with the verified provider email and no password
+	user, err := registerOIDCUser(c, provider.Name, identity)
+	if err != nil {
+		slog.ErrorContext(ctx, "Gagal mendaftarkan pengguna OIDC", "provider", provider.Name, "error", err)
+		metrics.RegistrationsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgCreateUserFailed)
+		return
+	}
+	metrics.RegistrationsTotal.WithLabelValues("success").Inc()
+	metrics.OIDCLoginsTotal.WithLabelValues(provider.Name, "registered").Inc()
+	finishLogin(c, user)
+}
+
+// LinkOIDCIdentity links a provider identity to an existing account after password confirmation
+// @Summary Link a provider identity to an existing account
+// @Description Confirms the account password for the link token returned by the OIDC callback, links the provider identity and signs the user in.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param   link  body  OIDCLinkRequest  true  "Link token and account password"
+// @Success 200 {object} SuccessResponse "JWT token, or challenge token when 2FA is enabled"
+// @Failure 400 {object} ErrorResponse "Invalid or expired link token"
+// @Failure 401 {object} ErrorResponse "Invalid password"
+// @Failure 409 {object} ErrorResponse "Provider account already linked to another user"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /auth/oidc/link [post]
+func LinkOIDCIdentity(c *gin.Context) {
+	var input OIDCLinkRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var pending oidcPendingLink
+	if err := utils.OpenToken(input.LinkToken, &pending); err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidLinkToken)
+		return
+	}
+
+	// Password confirmation shares the login attempt budget and lockout
+	if !guardAccount(c, "login", pending.Email, ratelimit.LoginPerAccount) {
+		return
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+	var user models.User
+	if err := db.Where("email = ?", pending.Email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusBadRequest, i18n.MsgInvalidLinkToken)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return
+	}
+
+	if !utils.CheckPasswordHash(input.Password, user.Password) {
+		recordAccountFailure(c, "login", pending.Email, ratelimit.LoginLockout)
+		respondError(c, http.StatusUnauthorized, i18n.MsgInvalidPassword)
+		return
+	}
+	resetAccountFailures(c, "login", pending.Email)
+	upgradePasswordHash(c, &user, input.Password)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.UserIdentity{
+			UserID:   user.ID,
+			Provider: pending.Provider,
+			Subject:  pending.Subject,
+			Email:    pending.Email,
+		}).Error; err != nil {
+			return err
+		}
+		// The provider has verified this email, so the account no longer needs the email code
+		if !user.EmailVerified {
+			user.EmailVerified = true
+			return tx.Model(&user).Updates(map[string]interface{}{"email_verified": true, "verification_code": ""}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			respondError(c, http.StatusConflict, i18n.MsgIdentityAlreadyLinked)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	metrics.OIDCLoginsTotal.WithLabelValues(pending.Provider, "linked").Inc()
+
+	finishLogin(c, &user)
+}
+
+// GetIdentities lists the provider identities linked to the authenticated user
+// @Summary List linked sign-in providers
+// @Description Returns the OpenID Connect identities linked to the authenticated user
+// @Tags User
+// @Produce  json
+// @Success 200 {object} SuccessResponse{data=[]models.UserIdentity} "Linked identities"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/identities [get]
+func GetIdentities(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var identities []models.UserIdentity
+	if err := config.DB.WithContext(c.Request.Context()).Where("user_id = ?", user.ID).Order("created_at").Find(&identities).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgIdentitiesFetched),
+		Data:    identities,
+	})
+}
+
+// UnlinkIdentity removes a linked provider identity from the authenticated user
+// @Summary Unlink a sign-in provider
+// @Description Removes the link to the given provider. The last sign-in method cannot be removed while the account has no password.
+// @Tags User
+// @Produce  json
+// @Param   provider  path  string  true  "Provider name, e.g. google"
+// @Success 200 {object} SuccessResponse "Account unlinked"
+// @Failure 400 {object} ErrorResponse "Cannot unlink the only sign-in method"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Linked account not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/identities/{provider} [delete]
+func UnlinkIdentity(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	db := config.DB.WithContext(c.Request.Context())
+
+	var identities []models.UserIdentity
+	if err := db.Where("user_id = ?", user.ID).Find(&identities).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	var target *models.UserIdentity
+	for i := range identities {
+		if identities[i].Provider == strings.ToLower(c.Param("provider")) {
+			target = &identities[i]
+			break
+		}
+	}
+	if target == nil {
+		respondError(c, http.StatusNotFound, i18n.MsgIdentityNotFound)
+		return
+	}
+
+	if user.Password == "" && len(identities) == 1 {
+		respondError(c, http.StatusBadRequest, i18n.MsgCannotUnlinkLastLogin)
+		return
+	}
+
+	if err := db.Delete(target).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgIdentityUnlinked),
+	})
+}
+
+// oidcProvider resolves the provider from the route, responding with an error when unavailable
+func oidcProvider(c *gin.Context) (*services.OIDCProvider, bool) {
+	provider, err := services.GetOIDCProvider(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, services.ErrOIDCProviderNotFound) {
+			respondError(c, http.StatusNotFound, i18n.MsgOIDCProviderNotFound)
+		} else {
+			slog.ErrorContext(c.Request.Context(), "Provider OIDC tidak tersedia", "provider", c.Param("provider"), "error", err)
+			respondError(c, http.StatusBadGateway, i18n.MsgOIDCAuthFailed)
+		}
+		return nil, false
+	}
+	return provider, true
+}
+
+// setOIDCStateCookie stores (or clears, with maxAge < 0) the state cookie for the OIDC callback path
+func setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	// Lax is required so the cookie is sent on the top-level redirect back from the provider
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, "/auth/oidc", "", middleware.IsHTTPS(c), true)
+}
+
+// registerOIDCUser creates a new account and its linked identity from verified provider claims
+func registerOIDCUser(c *gin.Context, provider string, identity *services.OIDCIdentity) (*models.User, error) {
+	var user *models.User
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		username, err := availableUsername(tx, identity.Email)
+		if err != nil {
+			return err
+		}
+
+		user = &models.User{
+			Email:         identity.Email,
+			Username:      username,
+			EmailVerified: true,
+			Language:      middleware.Language(c),
+		}
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.UserIdentity{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}).Error
+	})
+	return user, err
+}
+
+// availableUsername derives a unique username from the local part of an email address
+func availableUsername(tx *gorm.DB, email string) (string, error) {
+	base := usernameSanitizer.ReplaceAllString(strings.ToLower(strings.SplitN(email, "@", 2)[0]), "")
+	if len(base) > 20 {
+		base = base[:20]
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 5; attempt++ {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("username = ?", candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		suffix, err := utils.GenerateVerificationCode()
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s%s", base, strings.ToLower(suffix[:4]))
+	}
+	return "", errors.New("could not find an available username")
+}
+
+// isDuplicateKeyError reports whether err is a unique constraint violation
+func isDuplicateKeyError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "duplicate key value")
+}
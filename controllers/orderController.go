@@ -0,0 +1,249 @@
+// controllers/orderController.go
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/payment"
+	"github.com/mfuadfakhruzzaki/backend-api/promo"
+	"github.com/mfuadfakhruzzaki/backend-api/wallet"
+	"gorm.io/gorm"
+)
+
+// GetOrders returns the orders of the authenticated user, newest first
+// @Summary List orders
+// @Tags Orders
+// @Produce  json
+// @Success 200 {object} SuccessResponse{data=[]models.Order} "Orders"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/orders [get]
+func GetOrders(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var orders []models.Order
+	if err := config.DB.WithContext(c.Request.Context()).Preload("Package").
+		Where("user_id = ?", user.ID).Order("created_at DESC").Find(&orders).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgOrdersFetched),
+		Data:    orders,
+	})
+}
+
+// GetOrder returns one order of the authenticated user
+// @Summary Get an order
+// @Description Returns the order with its status. Clients can poll this endpoint after the user returns from the payment page.
+// @Tags Orders
+// @Produce  json
+// @Param   id  path  int  true  "Order ID"
+// @Success 200 {object} SuccessResponse{data=models.Order} "Order"
+// @Failure 404 {object} ErrorResponse "Order not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/orders/{id} [get]
+func GetOrder(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidOrderID)
+		return
+	}
+
+	var order models.Order
+	err = config.DB.WithContext(c.Request.Context()).Preload("Package").
+		Where("id = ? AND user_id = ?", orderID, user.ID).First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, i18n.MsgOrderNotFound)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgOrderFetched),
+		Data:    order,
+	})
+}
+
+// checkout returns the pending order of the line for the package with the same promo codes, or creates one
+// and starts its payment. created is false when an unpaid order that can still be paid is reused. An order
+// whose price is fully covered by promo codes is paid immediately without the gateway.
+func checkout(c *gin.Context, user *models.User, line *models.Line, pkg *models.Package, codes []string) (order models.Order, created bool, err error) {
+	db := config.DB.WithContext(c.Request.Context())
+
+	err = db.Where("line_id = ? AND package_id = ? AND promo_codes = ? AND status = ? AND expires_at > ? AND payment_url <> ''",
+		line.ID, pkg.ID, promo.JoinCodes(codes), models.OrderPending, time.Now()).Order("created_at DESC").First(&order).Error
+	if err == nil {
+		return order, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return order, false, err
+	}
+
+	if order, err = newOrder(models.OrderTypePackage, user, payment.Default().Name()); err != nil {
+		return order, false, err
+	}
+	order.LineID, order.PackageID = &line.ID, &pkg.ID
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := createPackageOrder(tx, user, pkg, codes, &order); err != nil {
+			return err
+		}
+		if order.Amount > 0 {
+			return nil
+		}
+		metrics.OrdersTotal.WithLabelValues(models.OrderPaid).Inc()
+		return fulfillPackageOrder(tx, &order)
+	})
+	if err != nil || order.Status == models.OrderPaid {
+		return order, err == nil, err
+	}
+
+	if err := startPayment(c, user, &order, pkg.Name, line.MSISDN); err != nil {
+		return order, false, err
+	}
+	return order, true, nil
+}
+
+// newOrder returns an unsaved pending order of the user with a new order number
+func newOrder(orderType string, user *models.User, gateway string) (models.Order, error) {
+	orderNumber, err := newOrderNumber()
+	if err != nil {
+		return models.Order{}, err
+	}
+	return models.Order{
+		OrderNumber: orderNumber,
+		Type:        orderType,
+		UserID:      user.ID,
+		Status:      models.OrderPending,
+		Gateway:     gateway,
+		ExpiresAt:   time.Now().Add(config.GetEnvDuration("PAYMENT_EXPIRY", 24*time.Hour)),
+	}, nil
+}
+
+// createPackageOrder prices the order from the package and the promo codes, then saves it with the promo
+// redemptions in transaction tx. Orders fully covered by promo codes are saved as paid.
+func createPackageOrder(tx *gorm.DB, user *models.User, pkg *models.Package, codes []string, order *models.Order) error {
+	quote, err := promo.Reserve(tx, user.ID, pkg, codes)
+	if err != nil {
+		return err
+	}
+	order.Currency = quote.Currency
+	order.OriginalAmount = quote.Price
+	order.Discount = quote.Discount
+	order.Amount = quote.Total
+	order.PromoCodes = quote.CodesString()
+	if order.Amount == 0 {
+		now := time.Now()
+		order.Status, order.Gateway, order.PaidAt = models.OrderPaid, models.GatewayPromo, &now
+	}
+
+	if err := tx.Create(order).Error; err != nil {
+		return err
+	}
+	if err := promo.Record(tx, quote, user.ID, order.ID); err != nil {
+		return err
+	}
+	metrics.PromoRedemptionsTotal.WithLabelValues("applied").Add(float64(len(quote.PromoCodes)))
+	return nil
+}
+
+// startPayment starts the payment of a saved pending order at the default gateway
+func startPayment(c *gin.Context, user *models.User, order *models.Order, description, phone string) error {
+	ctx := c.Request.Context()
+	db := config.DB.WithContext(ctx)
+	gateway := payment.Default()
+
+	session, err := gateway.CreatePayment(ctx, payment.Request{
+		OrderNumber:   order.OrderNumber,
+		Amount:        order.AmountMoney(),
+		Description:   description,
+		CustomerName:  user.Username,
+		CustomerEmail: user.Email,
+		CustomerPhone: phone,
+		ExpiresAt:     order.ExpiresAt,
+	})
+	if err != nil {
+		metrics.OrdersTotal.WithLabelValues("gateway_error").Inc()
+		slog.ErrorContext(ctx, "Gagal memulai pembayaran", "order", order.OrderNumber, "gateway", gateway.Name(), "error", err)
+		db.Model(order).Update("status", models.OrderFailed)
+		return errPaymentGateway
+	}
+
+	order.GatewayReference, order.PaymentURL = session.Reference, session.RedirectURL
+	err = db.Model(order).Updates(map[string]interface{}{
+		"gateway_reference": session.Reference,
+		"payment_url":       session.RedirectURL,
+	}).Error
+	if err != nil {
+		return err
+	}
+	metrics.OrdersTotal.WithLabelValues("created").Inc()
+	return nil
+}
+
+// checkoutWithWallet buys the package for the line with the wallet balance. The order is paid, the
+// wallet is debited and the subscription is activated in a single transaction.
+func checkoutWithWallet(c *gin.Context, user *models.User, line *models.Line, pkg *models.Package, codes []string) (order models.Order, err error) {
+	if order, err = newOrder(models.OrderTypePackage, user, models.GatewayWallet); err != nil {
+		return order, err
+	}
+	now := time.Now()
+	order.LineID, order.PackageID = &line.ID, &pkg.ID
+	order.Status, order.ExpiresAt, order.PaidAt = models.OrderPaid, now, &now
+
+	err = config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := createPackageOrder(tx, user, pkg, codes, &order); err != nil {
+			return err
+		}
+		if order.Amount > 0 {
+			_, err := wallet.Purchase(tx, user.ID, order.AmountMoney(), order.OrderNumber, pkg.Name)
+			metrics.WalletTransactionsTotal.WithLabelValues(models.JournalPurchase, metrics.Result(err)).Inc()
+			if err != nil {
+				return err
+			}
+		}
+		return fulfillPackageOrder(tx, &order)
+	})
+	if err != nil {
+		return order, err
+	}
+	metrics.OrdersTotal.WithLabelValues(models.OrderPaid).Inc()
+	return order, nil
+}
+
+// errPaymentGateway is returned when the gateway could not start the payment
+var errPaymentGateway = errors.New("payment gateway error")
+
+// newOrderNumber generates an order number such as ORD-20241019-9F3A2C1B that is safe to send to gateways
+func newOrderNumber() (string, error) {
+	raw := make([]byte, 4)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ORD-%s-%s", time.Now().UTC().Format("20060102"), strings.ToUpper(hex.EncodeToString(raw))), nil
+}
@@ -1,29 +1,64 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/mfuadfakhruzzaki/backend-api/config"
-	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
 	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/promo"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"github.com/mfuadfakhruzzaki/backend-api/wallet"
 )
 
 // GetPackages retrieves all available packages
 // @Summary Get all packages
-// @Description Retrieve a list of all available packages
+// @Description Retrieve a list of all available packages. With operator, only packages for that operator and packages for every operator are returned; operator=mine uses the operator of the line selected with line_id, or of the primary line.
 // @Tags Packages
+// @Param operator query string false "Operator name (telkomsel, indosat, xl, axis, tri, smartfren) or mine"
+// @Param line_id query int false "Line used by operator=mine (default: primary line)"
 // @Produce json
 // @Success 200 {array} models.Package "List of available packages"
+// @Failure 400 {object} map[string]string "Unknown operator or no operator detected for the user's phone number"
 // @Failure 500 {object} map[string]string "Error fetching packages"
-// @Router /packages [get]
+// @Router /api/packages [get]
 func GetPackages(c *gin.Context) {
+	query := config.DB.WithContext(c.Request.Context())
+
+	if operator := strings.ToLower(strings.TrimSpace(c.Query("operator"))); operator != "" {
+		if operator == "mine" {
+			user, ok := currentUser(c)
+			if !ok {
+				return
+			}
+			line, ok := lineFromQuery(c, &user)
+			if !ok {
+				return
+			}
+			if line == nil || line.Operator == "" {
+				respondError(c, http.StatusBadRequest, i18n.MsgPhoneOperatorUnknown)
+				return
+			}
+			operator = line.Operator
+		} else if !slices.Contains(utils.Operators, operator) {
+			respondError(c, http.StatusBadRequest, i18n.MsgInvalidOperator, strings.Join(utils.Operators, ", "))
+			return
+		}
+		// Paket tanpa operator berlaku untuk semua operator
+		query = query.Where("operator = ? OR operator = ''", operator)
+	}
+
 	var packages []models.Package
-	if err := config.DB.Find(&packages).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching packages"})
+	if err := query.Find(&packages).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgFetchPackagesFailed)
 		return
 	}
 
@@ -40,24 +75,24 @@ func GetPackages(c *gin.Context) {
 // @Failure 400 {object} map[string]string "Invalid package ID"
 // @Failure 404 {object} map[string]string "Package not found"
 // @Failure 500 {object} map[string]string "Error fetching package"
-// @Router /packages/{id} [get]
+// @Router /api/packages/{id} [get]
 func GetPackageByID(c *gin.Context) {
 	// Mengambil parameter 'id' dari URL
 	packageIDStr := c.Param("id")
 	packageID, err := strconv.Atoi(packageIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPackageID)
 		return
 	}
 
 	// Mencari paket berdasarkan ID
 	var pkg models.Package
-	result := config.DB.First(&pkg, packageID)
+	result := config.DB.WithContext(c.Request.Context()).First(&pkg, packageID)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+			respondError(c, http.StatusNotFound, i18n.MsgPackageNotFound)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching package"})
+			respondError(c, http.StatusInternalServerError, i18n.MsgFetchPackageFailed)
 		}
 		return
 	}
@@ -66,65 +101,111 @@ func GetPackageByID(c *gin.Context) {
 	c.JSON(http.StatusOK, pkg)
 }
 
-// SelectPackage allows a user to select a package by its ID
-// @Summary Select a package
-// @Description Allows a user to select a package by its ID, updates the user's selected package
+// SelectPackage starts the purchase of a package for a line
+// @Summary Buy a package
+// @Description Creates a pending order for a verified line (line_id, or the primary line by default) priced from the package, and starts its payment. Redirect the user to payment_url; the subscription is activated when the gateway confirms the payment. An unpaid order for the same line and package is reused. With pay_with=wallet the package is paid from the wallet balance and activated immediately. Promo codes given in promo are applied to the price; an order fully covered by promo codes is paid immediately.
 // @Tags Packages
 // @Param id path int true "Package ID"
+// @Param line_id query int false "Line to subscribe (default: primary line)"
+// @Param promo query string false "Comma-separated promo codes"
+// @Param pay_with query string false "gateway (default) or wallet to pay immediately with the wallet balance"
 // @Produce json
-// @Success 200 {object} map[string]interface{} "Package selected successfully, includes user and package information"
-// @Failure 400 {object} map[string]string "Invalid package ID"
+// @Success 201 {object} SuccessResponse{data=models.Order} "Order created, includes the payment URL; or order paid with the wallet"
+// @Success 200 {object} SuccessResponse{data=models.Order} "Existing unpaid order"
+// @Failure 400 {object} map[string]string "Invalid package ID or promo code cannot be used"
 // @Failure 401 {object} map[string]string "Unauthorized, user not found in context"
-// @Failure 404 {object} map[string]string "User not found"
-// @Failure 500 {object} map[string]string "Database error or error updating user package"
-// @Router /packages/{id} [post]
+// @Failure 402 {object} map[string]string "Insufficient wallet balance"
+// @Failure 403 {object} map[string]string "Phone number not verified"
+// @Failure 404 {object} map[string]string "User, package or promo code not found"
+// @Failure 409 {object} map[string]string "Promo code usage limit reached"
+// @Failure 500 {object} map[string]string "Database error"
+// @Failure 502 {object} map[string]string "Payment gateway error"
+// @Router /api/packages/{id}/select [post]
 func SelectPackage(c *gin.Context) {
 	// Retrieve the 'id' parameter from the URL
 	packageIDStr := c.Param("id")
 	packageID, err := strconv.Atoi(packageIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPackageID)
 		return
 	}
 
-	// Retrieve the user's email from the context (set by JWT middleware)
-	email, exists := c.Get(string(middleware.UserContextKey))
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User email not found in context"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	emailStr, ok := email.(string)
-	if !ok || emailStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user email in context"})
+	// Paket dipasang pada line, jadi kepemilikan nomornya harus sudah diverifikasi
+	line, ok := lineFromQuery(c, &user)
+	if !ok {
+		return
+	}
+	if line == nil || !line.Verified {
+		respondError(c, http.StatusForbidden, i18n.MsgPhoneNotVerified)
 		return
 	}
 
-	// Find the user by email
-	var user models.User
-	result := config.DB.Where("email = ?", emailStr).First(&user)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	// Make sure the package exists and has not been deleted
+	var pkg models.Package
+	if err := config.DB.WithContext(c.Request.Context()).First(&pkg, packageID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			respondError(c, http.StatusNotFound, i18n.MsgPackageNotFound)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
 		}
 		return
 	}
 
-	// Update the user's PackageID
-	pkgID := uint(packageID)
-	user.PackageID = &pkgID
+	codes := promo.ParseCodes(c.Query("promo"))
 
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating user package"})
+	switch payWith := c.DefaultQuery("pay_with", "gateway"); payWith {
+	case "gateway":
+	case "wallet":
+		order, err := checkoutWithWallet(c, &user, line, &pkg, codes)
+		if respondPromoError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, wallet.ErrInsufficientFunds):
+			respondError(c, http.StatusPaymentRequired, i18n.MsgInsufficientBalance)
+			return
+		case err != nil:
+			respondError(c, http.StatusInternalServerError, i18n.MsgCreateOrderFailed)
+			return
+		}
+		metrics.PackageSelectionsTotal.WithLabelValues(strconv.Itoa(packageID)).Inc()
+		order.Package = &pkg
+		c.JSON(http.StatusCreated, SuccessResponse{
+			Message: t(c, i18n.MsgPackagePurchasedWithWallet),
+			Data:    order,
+		})
+		return
+	default:
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPaymentMethod, payWith)
+		return
+	}
+
+	order, created, err := checkout(c, &user, line, &pkg, codes)
+	if respondPromoError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, errPaymentGateway):
+		respondError(c, http.StatusBadGateway, i18n.MsgPaymentGatewayFailed)
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, i18n.MsgCreateOrderFailed)
 		return
 	}
 
-	// Optionally, you can fetch the updated user or include additional information
-	c.JSON(http.StatusOK, gin.H{
-		"message":      "Package selected successfully",
-		"user":         user,
-		"selectedPack": packageID,
+	status := http.StatusOK
+	if created {
+		metrics.PackageSelectionsTotal.WithLabelValues(strconv.Itoa(packageID)).Inc()
+		status = http.StatusCreated
+	}
+	order.Package = &pkg
+	c.JSON(status, SuccessResponse{
+		Message: t(c, i18n.MsgOrderCreated),
+		Data:    order,
 	})
 }
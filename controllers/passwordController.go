@@ -0,0 +1,176 @@
+// controllers/passwordController.go
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"gorm.io/gorm"
+)
+
+// ChangePasswordRequest represents the request body for changing the password
+type ChangePasswordRequest struct {
+	// CurrentPassword may be omitted only by accounts that have no password yet (e.g. created with Google)
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ChangePassword changes the password of the authenticated user
+// @Summary Change password
+// @Description Changes the password after confirming the current one. The new password must satisfy the password policy and must not appear in the breached-password list. All other sessions are ended.
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param   request  body  ChangePasswordRequest  true  "Current and new password"
+// @Success 200 {object} SuccessResponse "Password changed"
+// @Failure 400 {object} ErrorResponse "New password rejected by the password policy"
+// @Failure 401 {object} ErrorResponse "Current password is wrong"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/password [put]
+func ChangePassword(c *gin.Context) {
+	var input ChangePasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	// Password confirmation shares the login attempt budget and lockout
+	if !guardAccount(c, "login", user.Email, ratelimit.LoginPerAccount) {
+		return
+	}
+
+	if user.Password != "" {
+		if !utils.CheckPasswordHash(input.CurrentPassword, user.Password) {
+			recordAccountFailure(c, "login", user.Email, ratelimit.LoginLockout)
+			metrics.PasswordChangesTotal.WithLabelValues("invalid_password").Inc()
+			respondError(c, http.StatusUnauthorized, i18n.MsgInvalidPassword)
+			return
+		}
+		resetAccountFailures(c, "login", user.Email)
+
+		if input.NewPassword == input.CurrentPassword {
+			metrics.PasswordChangesTotal.WithLabelValues("rejected").Inc()
+			respondError(c, http.StatusBadRequest, i18n.MsgPasswordUnchanged)
+			return
+		}
+	}
+
+	if !checkPasswordPolicy(c, input.NewPassword, user.Email, user.Username) {
+		metrics.PasswordChangesTotal.WithLabelValues("rejected").Inc()
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(input.NewPassword)
+	if err != nil {
+		metrics.PasswordChangesTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgHashPasswordFailed)
+		return
+	}
+
+	sessionID := c.GetUint(string(middleware.SessionIDContextKey))
+	var revoked int64
+	err = config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+		// Anyone holding the old password may already be signed in elsewhere
+		revoked, err = revokeSessions(tx, user.ID, sessionID)
+		return err
+	})
+	if err != nil {
+		metrics.PasswordChangesTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	metrics.PasswordChangesTotal.WithLabelValues("success").Inc()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgPasswordChanged),
+		Data:    gin.H{"revoked_sessions": revoked},
+	})
+}
+
+// checkPasswordPolicy validates a new password against the configured policy and the breached-password
+// list. If the password is rejected, the error response has already been sent and false is returned.
+func checkPasswordPolicy(c *gin.Context, password, email, username string) bool {
+	policy := config.LoadPasswordPolicy()
+
+	if key, args := passwordPolicyViolation(policy, password, email, username); key != "" {
+		respondError(c, http.StatusBadRequest, key, args...)
+		return false
+	}
+
+	// The breach check fails open so a broken list does not block registrations
+	breached, err := utils.IsBreachedPassword(password)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "Gagal memeriksa daftar password bocor", "error", err)
+	}
+	if breached {
+		respondError(c, http.StatusBadRequest, i18n.MsgPasswordBreached)
+		return false
+	}
+	return true
+}
+
+// passwordPolicyViolation returns the message key (and arguments) of the first rule the password breaks,
+// or an empty key if the password satisfies the policy
+func passwordPolicyViolation(policy config.PasswordPolicy, password, email, username string) (string, []interface{}) {
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		return i18n.MsgPasswordTooShort, []interface{}{policy.MinLength}
+	}
+	if policy.MaxLength > 0 && len(password) > policy.MaxLength {
+		return i18n.MsgPasswordTooLong, []interface{}{policy.MaxLength}
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	switch {
+	case policy.RequireUpper && !upper:
+		return i18n.MsgPasswordNeedsUpper, nil
+	case policy.RequireLower && !lower:
+		return i18n.MsgPasswordNeedsLower, nil
+	case policy.RequireDigit && !digit:
+		return i18n.MsgPasswordNeedsDigit, nil
+	case policy.RequireSymbol && !symbol:
+		return i18n.MsgPasswordNeedsSymbol, nil
+	}
+
+	if policy.DisallowPersonalInfo {
+		lowered := strings.ToLower(password)
+		localPart, _, _ := strings.Cut(strings.ToLower(email), "@")
+		for _, personal := range []string{localPart, strings.ToLower(username)} {
+			// Very short values such as "ab" would reject too many unrelated passwords
+			if len(personal) >= 3 && strings.Contains(lowered, personal) {
+				return i18n.MsgPasswordContainsPersonalInfo, nil
+			}
+		}
+	}
+	return "", nil
+}
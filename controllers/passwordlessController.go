@@ -0,0 +1,292 @@
+// controllers/passwordlessController.go
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"gorm.io/gorm"
+)
+
+// maxLoginCodeAttempts invalidates a login code after this many wrong guesses
+const maxLoginCodeAttempts = 5
+
+// LoginCodeRequest represents the request body for requesting a passwordless login code
+type LoginCodeRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// LoginCodeVerifyRequest represents the request body for completing a passwordless login.
+// Either email and code (typed by the user) or token (from the magic link) must be provided.
+type LoginCodeVerifyRequest struct {
+	Email string `json:"email" binding:"omitempty,email"`
+	Code  string `json:"code"`
+	Token string `json:"token"`
+}
+
+// RequestLoginCode sends a one-time login code and magic link to the user's email
+// @Summary Request a passwordless login code
+// @Description Sends a one-time code (and a magic link if configured) to the given email. The response is the same whether or not the account exists.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param   request  body  LoginCodeRequest  true  "Account email"
+// @Success 202 {object} SuccessResponse "Login code sent if the account exists"
+// @Failure 400 {object} ErrorResponse "Invalid request payload"
+// @Failure 404 {object} ErrorResponse "Passwordless login is not enabled"
+// @Failure 429 {object} ErrorResponse "Too many requests"
+// @Router  /auth/passwordless/request [post]
+func RequestLoginCode(c *gin.Context) {
+	if !passwordlessEnabled(c) {
+		return
+	}
+
+	var input LoginCodeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if !guardAccount(c, "login_code_request", input.Email, ratelimit.LoginCodeRequestPerAccount) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	db := config.DB.WithContext(ctx)
+
+	var user models.User
+	err := db.Where("LOWER(email) = ?", accountKey(input.Email)).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	// Unknown emails get the same response so the endpoint cannot reveal registered accounts
+	if err == nil {
+		code, token, err := createLoginCode(db, user.ID)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, i18n.MsgLoginCodeFailed)
+			return
+		}
+
+		// Sending happens in the background so the response time does not depend on whether the account exists
+		validFor := loginCodeTTL()
+		link := magicLink(token)
+		sendCtx := context.WithoutCancel(ctx)
+		services.StartWorker("login-code-email", func(context.Context) {
+			if err := utils.SendLoginCodeEmail(sendCtx, user.Email, code, link, validFor, user.Language); err != nil {
+				slog.ErrorContext(sendCtx, "Gagal mengirim kode login", "user_id", user.ID, "error", err)
+			}
+		})
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: t(c, i18n.MsgLoginCodeSent),
+	})
+}
+
+// VerifyLoginCode completes a passwordless login with an emailed code or magic link token
+// @Summary Complete a passwordless login
+// @Description Exchanges an emailed one-time code (with the email) or a magic link token for the same JWT token returned by /auth/login. Codes expire quickly and can be used only once.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param   request  body  LoginCodeVerifyRequest  true  "Email and code, or magic link token"
+// @Success 200 {object} SuccessResponse "JWT token, or challenge token when 2FA is enabled"
+// @Failure 400 {object} ErrorResponse "Invalid request payload"
+// @Failure 401 {object} ErrorResponse "Invalid or expired login code"
+// @Failure 404 {object} ErrorResponse "Passwordless login is not enabled"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Router  /auth/passwordless/verify [post]
+func VerifyLoginCode(c *gin.Context) {
+	if !passwordlessEnabled(c) {
+		return
+	}
+
+	var input LoginCodeVerifyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var (
+		user models.User
+		ok   bool
+		err  error
+	)
+	switch {
+	case input.Token != "":
+		user, ok, err = consumeLoginToken(c, input.Token)
+	case input.Email != "" && input.Code != "":
+		if !guardAccount(c, "login_code", input.Email, ratelimit.LoginCodeVerifyPerAccount) {
+			metrics.LoginsTotal.WithLabelValues("rate_limited").Inc()
+			return
+		}
+		user, ok, err = consumeLoginCode(c, input.Email, input.Code)
+		if err == nil && !ok {
+			recordAccountFailure(c, "login_code", input.Email, ratelimit.LoginLockout)
+		}
+	default:
+		respondError(c, http.StatusBadRequest, i18n.MsgLoginCodeRequestInvalid)
+		return
+	}
+
+	if err != nil {
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	if !ok {
+		metrics.LoginsTotal.WithLabelValues("invalid_login_code").Inc()
+		respondError(c, http.StatusUnauthorized, i18n.MsgInvalidLoginCode)
+		return
+	}
+	resetAccountFailures(c, "login_code", user.Email)
+
+	// Receiving the code proves ownership of the email address
+	if !user.EmailVerified {
+		user.EmailVerified = true
+		config.DB.WithContext(c.Request.Context()).Model(&user).Updates(map[string]interface{}{"email_verified": true, "verification_code": ""})
+	}
+
+	finishLogin(c, &user)
+}
+
+// consumeLoginCode checks the latest active code for the account and marks it as used
+func consumeLoginCode(c *gin.Context, email, code string) (models.User, bool, error) {
+	db := config.DB.WithContext(c.Request.Context())
+
+	var user models.User
+	if err := db.Where("LOWER(email) = ?", accountKey(email)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, false, nil
+		}
+		return user, false, err
+	}
+
+	var loginCode models.LoginCode
+	err := db.Where("user_id = ? AND used_at IS NULL AND expires_at > ? AND attempts < ?", user.ID, time.Now(), maxLoginCodeAttempts).
+		Order("created_at DESC").First(&loginCode).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, false, nil
+		}
+		return user, false, err
+	}
+
+	expected := utils.HashToken(strings.ToUpper(strings.TrimSpace(code)))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(loginCode.CodeHash)) != 1 {
+		db.Model(&loginCode).UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+		return user, false, nil
+	}
+
+	used, err := markLoginCodeUsed(db, loginCode.ID)
+	return user, used, err
+}
+
+// consumeLoginToken validates a magic link token and marks it as used
+func consumeLoginToken(c *gin.Context, token string) (models.User, bool, error) {
+	db := config.DB.WithContext(c.Request.Context())
+
+	var user models.User
+	var loginCode models.LoginCode
+	err := db.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(token), time.Now()).First(&loginCode).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, false, nil
+		}
+		return user, false, err
+	}
+
+	used, err := markLoginCodeUsed(db, loginCode.ID)
+	if err != nil || !used {
+		return user, false, err
+	}
+
+	if err := db.Where("id = ?", loginCode.UserID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, false, nil
+		}
+		return user, false, err
+	}
+	return user, true, nil
+}
+
+// markLoginCodeUsed atomically consumes a login code so concurrent requests cannot reuse it
+func markLoginCodeUsed(db *gorm.DB, id uint) (bool, error) {
+	result := db.Model(&models.LoginCode{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// createLoginCode replaces any previous codes of the user with a new code and magic link token
+func createLoginCode(db *gorm.DB, userID uint) (code, token string, err error) {
+	code, err = utils.GenerateVerificationCode()
+	if err != nil {
+		return "", "", err
+	}
+	token, err = utils.RandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.LoginCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.LoginCode{
+			UserID:    userID,
+			CodeHash:  utils.HashToken(code),
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: time.Now().Add(loginCodeTTL()),
+		}).Error
+	})
+	return code, token, err
+}
+
+// passwordlessEnabled responds with 404 when passwordless login is turned off for this deployment
+func passwordlessEnabled(c *gin.Context) bool {
+	if !config.GetEnvBool("PASSWORDLESS_ENABLED", false) {
+		respondError(c, http.StatusNotFound, i18n.MsgPasswordlessDisabled)
+		return false
+	}
+	return true
+}
+
+func loginCodeTTL() time.Duration {
+	return config.GetEnvDuration("PASSWORDLESS_CODE_TTL", 10*time.Minute)
+}
+
+// magicLink builds the link to the client app, which posts the token to /auth/passwordless/verify.
+// The link does not log in by itself so that email scanners prefetching it cannot consume the token.
+func magicLink(token string) string {
+	return clientLink(config.GetEnv("PASSWORDLESS_LINK_URL", ""), token)
+}
+
+// clientLink appends the token as a query parameter to a client app URL, or returns "" if no URL is configured
+func clientLink(base, token string) string {
+	if base == "" {
+		return ""
+	}
+	link, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
@@ -0,0 +1,195 @@
+// controllers/paymentController.go
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/invoice"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/payment"
+	"github.com/mfuadfakhruzzaki/backend-api/wallet"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxWebhookBodySize limits the payment notification body that is read into memory
+const maxWebhookBodySize = 64 << 10
+
+var (
+	// errNotificationProcessed is returned when the same notification was already handled
+	errNotificationProcessed = errors.New("payment notification already processed")
+	// errPaymentAmountMismatch is returned when the paid amount differs from the order amount
+	errPaymentAmountMismatch = errors.New("paid amount does not match the order")
+)
+
+// PaymentWebhook receives payment notifications from the payment gateway
+// @Summary Payment notification webhook
+// @Description Called by the payment gateway when the payment status of an order changes. The signature is verified and each notification is processed only once. The subscription of the line is activated when the payment is settled.
+// @Tags Payments
+// @Accept  json
+// @Produce  json
+// @Param   gateway  path  string  true  "Gateway name (fake or midtrans)"
+// @Success 200 {object} SuccessResponse "Notification processed"
+// @Failure 400 {object} ErrorResponse "Invalid notification or amount mismatch"
+// @Failure 401 {object} ErrorResponse "Invalid signature"
+// @Failure 404 {object} ErrorResponse "Unknown gateway or order"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /webhooks/payments/{gateway} [post]
+func PaymentWebhook(c *gin.Context) {
+	gateway, ok := payment.Lookup(c.Param("gateway"))
+	if !ok {
+		respondError(c, http.StatusNotFound, i18n.MsgPaymentGatewayNotFound)
+		return
+	}
+	name := gateway.Name()
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPaymentNotification)
+		return
+	}
+
+	notification, err := gateway.ParseNotification(c.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			metrics.PaymentNotificationsTotal.WithLabelValues(name, "invalid_signature").Inc()
+			slog.WarnContext(c.Request.Context(), "Tanda tangan notifikasi pembayaran tidak valid", "gateway", name)
+			respondError(c, http.StatusUnauthorized, i18n.MsgInvalidPaymentSignature)
+			return
+		}
+		metrics.PaymentNotificationsTotal.WithLabelValues(name, "invalid").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPaymentNotification)
+		return
+	}
+
+	err = config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		return applyPaymentNotification(tx, name, &notification, body)
+	})
+	switch {
+	case errors.Is(err, errNotificationProcessed):
+		// Gateways retry until they receive a 2xx, so duplicates are acknowledged
+		metrics.PaymentNotificationsTotal.WithLabelValues(name, "duplicate").Inc()
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		metrics.PaymentNotificationsTotal.WithLabelValues(name, "not_found").Inc()
+		respondError(c, http.StatusNotFound, i18n.MsgOrderNotFound)
+		return
+	case errors.Is(err, errPaymentAmountMismatch):
+		metrics.PaymentNotificationsTotal.WithLabelValues(name, "amount_mismatch").Inc()
+		slog.ErrorContext(c.Request.Context(), "Nominal pembayaran tidak sesuai dengan order",
+			"gateway", name, "order", notification.OrderNumber, "amount", notification.Amount.Decimal())
+		respondError(c, http.StatusBadRequest, i18n.MsgPaymentAmountMismatch)
+		return
+	case err != nil:
+		metrics.PaymentNotificationsTotal.WithLabelValues(name, "failure").Inc()
+		slog.ErrorContext(c.Request.Context(), "Gagal memproses notifikasi pembayaran", "gateway", name, "error", err)
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	default:
+		metrics.PaymentNotificationsTotal.WithLabelValues(name, "success").Inc()
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgPaymentNotificationProcessed),
+	})
+}
+
+// applyPaymentNotification records the notification and moves the order to its new status. The order row
+// is locked so that concurrent deliveries of the same payment are applied one after another.
+func applyPaymentNotification(tx *gorm.DB, gateway string, n *payment.Notification, payload []byte) error {
+	var order models.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_number = ? AND gateway = ?", n.OrderNumber, gateway).First(&order).Error
+	if err != nil {
+		return err
+	}
+
+	// The unique (gateway, event_id) index rejects notifications that were already processed
+	err = tx.Create(&models.PaymentNotification{
+		Gateway: gateway,
+		EventID: n.EventID,
+		OrderID: order.ID,
+		Status:  string(n.Status),
+		Payload: string(payload),
+	}).Error
+	if isDuplicateKeyError(err) {
+		return errNotificationProcessed
+	}
+	if err != nil {
+		return err
+	}
+
+	switch n.Status {
+	case payment.StatusPaid:
+		if order.Status == models.OrderPaid || order.Status == models.OrderRefunded {
+			return nil
+		}
+		if !n.Amount.Equal(order.AmountMoney()) {
+			return errPaymentAmountMismatch
+		}
+		// A settlement that arrives after the order expired is still honoured, since the user was charged
+		now := time.Now()
+		if err := tx.Model(&order).Updates(map[string]interface{}{
+			"status":            models.OrderPaid,
+			"paid_at":           now,
+			"gateway_reference": n.Reference,
+		}).Error; err != nil {
+			return err
+		}
+		metrics.OrdersTotal.WithLabelValues(models.OrderPaid).Inc()
+		order.Status, order.PaidAt = models.OrderPaid, &now
+		if order.Type == models.OrderTypeTopUp {
+			_, err := wallet.TopUp(tx, order.UserID, order.AmountMoney(), order.OrderNumber)
+			metrics.WalletTransactionsTotal.WithLabelValues(models.JournalTopUp, metrics.Result(err)).Inc()
+			return err
+		}
+		return fulfillPackageOrder(tx, &order)
+	case payment.StatusFailed, payment.StatusExpired:
+		if order.Status != models.OrderPending {
+			return nil
+		}
+		status := models.OrderFailed
+		if n.Status == payment.StatusExpired {
+			status = models.OrderExpired
+		}
+		metrics.OrdersTotal.WithLabelValues(status).Inc()
+		return tx.Model(&order).Update("status", status).Error
+	}
+	return nil
+}
+
+// fulfillPackageOrder activates the subscription of a paid package order and issues its invoice. The invoice
+// is issued in the same transaction so that every paid order has exactly one invoice.
+func fulfillPackageOrder(tx *gorm.DB, order *models.Order) error {
+	if err := activateOrder(tx, order); err != nil {
+		return err
+	}
+	if _, err := invoice.Issue(tx, order); err != nil {
+		return err
+	}
+	metrics.InvoicesTotal.WithLabelValues("issued").Inc()
+	return nil
+}
+
+// activateOrder subscribes the line of a paid order to its package
+func activateOrder(tx *gorm.DB, order *models.Order) error {
+	if order.LineID == nil || order.PackageID == nil {
+		return nil
+	}
+	result := tx.Model(&models.Line{}).Where("id = ? AND user_id = ?", *order.LineID, order.UserID).Update("package_id", *order.PackageID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		slog.Warn("Line untuk order yang sudah dibayar tidak ditemukan", "order", order.OrderNumber, "line_id", *order.LineID)
+		return nil
+	}
+	return syncPrimaryLine(tx, order.UserID)
+}
@@ -0,0 +1,285 @@
+// controllers/phoneVerificationController.go
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+	"github.com/mfuadfakhruzzaki/backend-api/sms"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// phoneOTPDigits is the length of the numeric code sent by SMS
+	phoneOTPDigits = 6
+	// maxPhoneOTPAttempts invalidates a phone verification code after this many wrong guesses
+	maxPhoneOTPAttempts = 5
+)
+
+// errPhoneVerificationConsumed is returned when a concurrent request already used the code
+var errPhoneVerificationConsumed = errors.New("phone verification already processed")
+
+// PhoneVerificationRequest represents the request body for confirming the phone number
+type PhoneVerificationRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RequestPhoneVerification sends a one-time code by SMS to the primary line of the authenticated user
+// @Summary Send phone verification code
+// @Description Sends a numeric one-time code by SMS to the phone number in the profile (the primary line). Any previous code is invalidated. Confirm it with /api/users/phone/verify.
+// @Tags User
+// @Produce  json
+// @Success 202 {object} SuccessResponse "Code sent"
+// @Failure 400 {object} ErrorResponse "No phone number or phone number already verified"
+// @Failure 429 {object} ErrorResponse "Too many requests"
+// @Failure 500 {object} ErrorResponse "Failed to send the code"
+// @Router  /api/users/phone/verify/request [post]
+func RequestPhoneVerification(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	line, ok := lineFromQuery(c, &user)
+	if !ok {
+		return
+	}
+	if line == nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgPhoneNumberRequired)
+		return
+	}
+	sendLineVerification(c, &user, line)
+}
+
+// RequestLineVerification sends a one-time code by SMS to a line of the authenticated user
+// @Summary Send line verification code
+// @Description Sends a numeric one-time code by SMS to the line. Any previous code for the line is invalidated.
+// @Tags Lines
+// @Produce  json
+// @Param   id  path  int  true  "Line ID"
+// @Success 202 {object} SuccessResponse "Code sent"
+// @Failure 400 {object} ErrorResponse "Line already verified"
+// @Failure 404 {object} ErrorResponse "Line not found"
+// @Failure 429 {object} ErrorResponse "Too many requests"
+// @Failure 500 {object} ErrorResponse "Failed to send the code"
+// @Router  /api/users/lines/{id}/verify/request [post]
+func RequestLineVerification(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	line, ok := findLine(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+	sendLineVerification(c, &user, &line)
+}
+
+// ConfirmPhoneVerification marks the primary line as verified after the SMS code is confirmed
+// @Summary Verify phone number
+// @Description Verifies the code sent by SMS to the phone number in the profile (the primary line). The code is only valid for the number it was sent to; changing the number requires a new code.
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param   request  body  PhoneVerificationRequest  true  "Code received by SMS"
+// @Success 200 {object} SuccessResponse "Phone number verified"
+// @Failure 400 {object} ErrorResponse "Invalid or expired code"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/phone/verify [post]
+func ConfirmPhoneVerification(c *gin.Context) {
+	var input PhoneVerificationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	line, ok := lineFromQuery(c, &user)
+	if !ok {
+		return
+	}
+	if line == nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgPhoneNumberRequired)
+		return
+	}
+	confirmLineVerification(c, &user, line, input.Code)
+}
+
+// ConfirmLineVerification marks a line as verified after the SMS code is confirmed
+// @Summary Verify a line
+// @Description Verifies the code sent by SMS to the line. A package can only be selected for a verified line.
+// @Tags Lines
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                       true  "Line ID"
+// @Param   request  body  PhoneVerificationRequest  true  "Code received by SMS"
+// @Success 200 {object} SuccessResponse "Line verified"
+// @Failure 400 {object} ErrorResponse "Invalid or expired code"
+// @Failure 404 {object} ErrorResponse "Line not found"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/lines/{id}/verify [post]
+func ConfirmLineVerification(c *gin.Context) {
+	var input PhoneVerificationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	line, ok := findLine(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+	confirmLineVerification(c, &user, &line, input.Code)
+}
+
+// sendLineVerification creates a new code for the line and sends it by SMS
+func sendLineVerification(c *gin.Context, user *models.User, line *models.Line) {
+	if line.Verified {
+		respondError(c, http.StatusBadRequest, i18n.MsgPhoneAlreadyVerified)
+		return
+	}
+
+	// SMS costs money, so both the account and the destination number are limited
+	if !guardAccount(c, "phone_otp_request", user.Email, ratelimit.PhoneOTPRequestPerAccount) ||
+		!guardAccount(c, "phone_otp_number", line.MSISDN, ratelimit.PhoneOTPRequestPerNumber) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	code, err := createPhoneVerification(config.DB.WithContext(ctx), line)
+	if err != nil {
+		metrics.PhoneVerificationsTotal.WithLabelValues("request", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgPhoneVerificationFailed)
+		return
+	}
+
+	if err := sms.SendPhoneVerificationCode(ctx, line.MSISDN, code, phoneOTPTTL(), user.Language); err != nil {
+		metrics.PhoneVerificationsTotal.WithLabelValues("request", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgSendSMSFailed)
+		return
+	}
+	metrics.PhoneVerificationsTotal.WithLabelValues("request", "success").Inc()
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: t(c, i18n.MsgPhoneVerificationSent, line.MSISDN),
+	})
+}
+
+// confirmLineVerification checks the code sent to the line and marks the line as verified
+func confirmLineVerification(c *gin.Context, user *models.User, line *models.Line, code string) {
+	if line.Verified {
+		respondError(c, http.StatusBadRequest, i18n.MsgPhoneAlreadyVerified)
+		return
+	}
+
+	if !guardAccount(c, "phone_otp", user.Email, ratelimit.PhoneOTPVerifyPerAccount) {
+		return
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+
+	var verification models.PhoneVerification
+	err := db.Where("line_id = ? AND phone_number = ? AND verified_at IS NULL AND expires_at > ? AND attempts < ?",
+		line.ID, line.MSISDN, time.Now(), maxPhoneOTPAttempts).
+		Order("created_at DESC").First(&verification).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	expected := utils.HashToken(strings.TrimSpace(code))
+	if err != nil || subtle.ConstantTimeCompare([]byte(expected), []byte(verification.CodeHash)) != 1 {
+		if err == nil {
+			db.Model(&verification).UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+		}
+		recordAccountFailure(c, "phone_otp", user.Email, ratelimit.PhoneOTPLockout)
+		metrics.PhoneVerificationsTotal.WithLabelValues("confirm", "invalid_code").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPhoneVerificationCode)
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PhoneVerification{}).Where("id = ? AND verified_at IS NULL", verification.ID).
+			Update("verified_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errPhoneVerificationConsumed
+		}
+		// The number may have changed since the code was sent
+		result = tx.Model(&models.Line{}).Where("id = ? AND msisdn = ?", line.ID, verification.PhoneNumber).
+			Update("verified", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errPhoneVerificationConsumed
+		}
+		if line.IsPrimary {
+			return syncPrimaryLine(tx, user.ID)
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errPhoneVerificationConsumed):
+		metrics.PhoneVerificationsTotal.WithLabelValues("confirm", "invalid_code").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPhoneVerificationCode)
+		return
+	case err != nil:
+		metrics.PhoneVerificationsTotal.WithLabelValues("confirm", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	resetAccountFailures(c, "phone_otp", user.Email)
+	metrics.PhoneVerificationsTotal.WithLabelValues("confirm", "success").Inc()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgPhoneVerified),
+		Data:    gin.H{"line_id": line.ID, "phone_number": line.MSISDN, "phone_verified": true},
+	})
+}
+
+// createPhoneVerification replaces any pending code of the line with a new one for its current number
+func createPhoneVerification(db *gorm.DB, line *models.Line) (string, error) {
+	code, err := utils.GenerateNumericCode(phoneOTPDigits)
+	if err != nil {
+		return "", err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("line_id = ? AND verified_at IS NULL", line.ID).Delete(&models.PhoneVerification{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.PhoneVerification{
+			UserID:      line.UserID,
+			LineID:      line.ID,
+			PhoneNumber: line.MSISDN,
+			CodeHash:    utils.HashToken(code),
+			ExpiresAt:   time.Now().Add(phoneOTPTTL()),
+		}).Error
+	})
+	return code, err
+}
+
+func phoneOTPTTL() time.Duration {
+	return config.GetEnvDuration("PHONE_OTP_TTL", 5*time.Minute)
+}
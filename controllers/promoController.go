@@ -0,0 +1,314 @@
+// controllers/promoController.go
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+	"github.com/mfuadfakhruzzaki/backend-api/promo"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// PromoRequest represents the request body for creating or replacing a promo code
+type PromoRequest struct {
+	Code         string `json:"code" binding:"required,max=40"`
+	Description  string `json:"description" binding:"max=255"`
+	Active       *bool  `json:"active"`
+	DiscountType string `json:"discount_type" binding:"required,oneof=percent fixed"`
+	// PercentBasisPoints is the percentage discount in basis points (1000 = 10%)
+	PercentBasisPoints int64 `json:"percent_basis_points"`
+	// MaxDiscount caps a percentage discount in whole rupiah (0 = no cap)
+	MaxDiscount int64 `json:"max_discount"`
+	// Amount is the fixed discount in whole rupiah
+	Amount         int64      `json:"amount"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	MaxUses        int64      `json:"max_uses"`
+	MaxUsesPerUser int64      `json:"max_uses_per_user"`
+	// Categories and PackageIDs restrict the packages the code applies to; both empty means every package
+	Categories []string `json:"categories"`
+	PackageIDs []uint   `json:"package_ids"`
+	Stackable  bool     `json:"stackable"`
+}
+
+// GetPackageQuote returns the price of a package after the given promo codes
+// @Summary Quote a package price
+// @Description Calculates the price of the package after the promo codes without redeeming them. Percentage discounts are applied first, then fixed discounts, each on the remaining price. Several codes can only be combined when all of them are stackable.
+// @Tags Packages
+// @Produce  json
+// @Param   id     path   int     true   "Package ID"
+// @Param   promo  query  string  false  "Comma-separated promo codes"
+// @Success 200 {object} SuccessResponse{data=promo.Quote} "Price after promo codes"
+// @Failure 400 {object} ErrorResponse "Invalid package ID or promo code cannot be used"
+// @Failure 404 {object} ErrorResponse "Package or promo code not found"
+// @Failure 409 {object} ErrorResponse "Promo code usage limit reached"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/packages/{id}/quote [get]
+func GetPackageQuote(c *gin.Context) {
+	packageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPackageID)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+	var pkg models.Package
+	if err := db.First(&pkg, packageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, i18n.MsgPackageNotFound)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return
+	}
+
+	quote, err := promo.Preview(db, user.ID, &pkg, promo.ParseCodes(c.Query("promo")))
+	if respondPromoError(c, err) {
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgQuoteFetched),
+		Data:    quote,
+	})
+}
+
+// respondPromoError sends the response for an error caused by a promo code and reports whether it did so.
+// Other errors are left to the caller.
+func respondPromoError(c *gin.Context, err error) bool {
+	if errors.Is(err, promo.ErrTooManyCodes) {
+		metrics.PromoRedemptionsTotal.WithLabelValues("rejected").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgTooManyPromoCodes, promo.MaxCodes())
+		return true
+	}
+
+	var codeErr *promo.CodeError
+	if !errors.As(err, &codeErr) {
+		return false
+	}
+	metrics.PromoRedemptionsTotal.WithLabelValues("rejected").Inc()
+	switch {
+	case errors.Is(err, promo.ErrNotFound):
+		respondError(c, http.StatusNotFound, i18n.MsgPromoNotFound, codeErr.Code)
+	case errors.Is(err, promo.ErrNotActive):
+		respondError(c, http.StatusBadRequest, i18n.MsgPromoNotActive, codeErr.Code)
+	case errors.Is(err, promo.ErrNotEligible):
+		respondError(c, http.StatusBadRequest, i18n.MsgPromoNotEligible, codeErr.Code)
+	case errors.Is(err, promo.ErrNotStackable):
+		respondError(c, http.StatusBadRequest, i18n.MsgPromoNotStackable, codeErr.Code)
+	case errors.Is(err, promo.ErrUsageLimit):
+		respondError(c, http.StatusConflict, i18n.MsgPromoUsageLimit, codeErr.Code)
+	case errors.Is(err, promo.ErrUserUsageLimit):
+		respondError(c, http.StatusConflict, i18n.MsgPromoUserUsageLimit, codeErr.Code)
+	default:
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+	}
+	return true
+}
+
+// AdminGetPromos returns all promo codes, newest first
+// @Summary List promo codes (admin)
+// @Tags Admin
+// @Produce  json
+// @Success 200 {object} SuccessResponse{data=[]models.PromoCode} "Promo codes"
+// @Failure 403 {object} ErrorResponse "Admin access required"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/admin/promos [get]
+func AdminGetPromos(c *gin.Context) {
+	if _, ok := currentAdmin(c); !ok {
+		return
+	}
+
+	var promos []models.PromoCode
+	if err := config.DB.WithContext(c.Request.Context()).Order("created_at DESC").Find(&promos).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgPromosFetched),
+		Data:    promos,
+	})
+}
+
+// AdminCreatePromo creates a promo code
+// @Summary Create a promo code (admin)
+// @Description Creates a percentage or fixed discount code. The code is stored in upper case and is active unless active is false.
+// @Tags Admin
+// @Accept  json
+// @Produce  json
+// @Param   request  body  PromoRequest  true  "Promo code"
+// @Success 201 {object} SuccessResponse{data=models.PromoCode} "Promo code created"
+// @Failure 400 {object} ErrorResponse "Invalid promo code"
+// @Failure 403 {object} ErrorResponse "Admin access required"
+// @Failure 409 {object} ErrorResponse "Promo code already exists"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/admin/promos [post]
+func AdminCreatePromo(c *gin.Context) {
+	var input PromoRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	var code models.PromoCode
+	if !applyPromoRequest(c, &input, &code) {
+		return
+	}
+	err := config.DB.WithContext(c.Request.Context()).Create(&code).Error
+	if isDuplicateKeyError(err) {
+		respondError(c, http.StatusConflict, i18n.MsgPromoCodeTaken)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "Kode promo dibuat oleh admin", "admin_id", admin.ID, "code", code.Code)
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: t(c, i18n.MsgPromoCreated),
+		Data:    code,
+	})
+}
+
+// AdminUpdatePromo replaces the settings of a promo code
+// @Summary Update a promo code (admin)
+// @Description Replaces every setting of the promo code. Redemptions made before the change are kept and still count towards the usage caps.
+// @Tags Admin
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int           true  "Promo code ID"
+// @Param   request  body  PromoRequest  true  "Promo code"
+// @Success 200 {object} SuccessResponse{data=models.PromoCode} "Promo code updated"
+// @Failure 400 {object} ErrorResponse "Invalid promo code"
+// @Failure 403 {object} ErrorResponse "Admin access required"
+// @Failure 404 {object} ErrorResponse "Promo code not found"
+// @Failure 409 {object} ErrorResponse "Promo code already exists"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/admin/promos/{id} [put]
+func AdminUpdatePromo(c *gin.Context) {
+	var input PromoRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	promoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPromoID)
+		return
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+	var code models.PromoCode
+	if err := db.Where("id = ?", promoID).First(&code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, i18n.MsgPromoNotFound, c.Param("id"))
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return
+	}
+
+	if !applyPromoRequest(c, &input, &code) {
+		return
+	}
+	// Select("*") so that false and zero values (active, stackable, caps) are saved too
+	err = db.Model(&code).Select("*").Omit("created_at").Updates(&code).Error
+	if isDuplicateKeyError(err) {
+		respondError(c, http.StatusConflict, i18n.MsgPromoCodeTaken)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "Kode promo diperbarui oleh admin", "admin_id", admin.ID, "code", code.Code)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgPromoUpdated),
+		Data:    code,
+	})
+}
+
+// applyPromoRequest validates the request and copies it to the promo code. If the request is invalid,
+// the 400 response has already been sent and ok is false.
+func applyPromoRequest(c *gin.Context, input *PromoRequest, code *models.PromoCode) (ok bool) {
+	codes := promo.ParseCodes(input.Code)
+	switch {
+	case len(codes) != 1 || strings.ContainsAny(codes[0], " \t"):
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPromo, "code must be a single word without commas")
+		return false
+	case input.DiscountType == models.DiscountPercent && (input.PercentBasisPoints < 1 || input.PercentBasisPoints > 10000):
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPromo, "percent_basis_points must be between 1 and 10000")
+		return false
+	case input.DiscountType == models.DiscountFixed && input.Amount <= 0:
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPromo, "amount must be positive")
+		return false
+	case input.MaxDiscount < 0 || input.MaxUses < 0 || input.MaxUsesPerUser < 0:
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPromo, "max_discount, max_uses and max_uses_per_user must not be negative")
+		return false
+	case input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt):
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPromo, "ends_at must be after starts_at")
+		return false
+	}
+
+	categories := []string{}
+	for _, category := range input.Categories {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, category)
+		}
+	}
+
+	code.Code = codes[0]
+	code.Description = strings.TrimSpace(input.Description)
+	code.Active = input.Active == nil || *input.Active
+	code.DiscountType = input.DiscountType
+	code.PercentBasisPoints, code.MaxDiscount, code.Amount = 0, 0, 0
+	if input.DiscountType == models.DiscountPercent {
+		code.PercentBasisPoints, code.MaxDiscount = input.PercentBasisPoints, input.MaxDiscount
+	} else {
+		code.Amount = input.Amount
+	}
+	code.Currency = money.IDR
+	code.StartsAt, code.EndsAt = input.StartsAt, input.EndsAt
+	code.MaxUses, code.MaxUsesPerUser = input.MaxUses, input.MaxUsesPerUser
+	code.Categories = datatypes.JSONSlice[string](categories)
+	code.PackageIDs = datatypes.JSONSlice[uint]{}
+	if input.PackageIDs != nil {
+		code.PackageIDs = input.PackageIDs
+	}
+	code.Stackable = input.Stackable
+	return true
+}
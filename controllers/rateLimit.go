@@ -0,0 +1,49 @@
+// controllers/rateLimit.go
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+)
+
+// accountKey menormalkan email agar variasi huruf besar/kecil berbagi batas yang sama
+func accountKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// guardAccount memeriksa lockout dan rate limit per akun untuk scope tertentu.
+// Mengembalikan false dan mengirim respons 429 jika request harus ditolak.
+func guardAccount(c *gin.Context, scope, email string, limit ratelimit.Limit) bool {
+	ctx := c.Request.Context()
+	account := accountKey(email)
+
+	if remaining := ratelimit.LockedFor(ctx, scope, account); remaining > 0 {
+		metrics.RateLimitedTotal.WithLabelValues(scope + "_locked").Inc()
+		middleware.AbortTooManyRequests(c, remaining, i18n.MsgAccountLocked)
+		return false
+	}
+
+	if result := ratelimit.Allow(ctx, scope+":account:"+account, limit); !result.Allowed {
+		metrics.RateLimitedTotal.WithLabelValues(scope + "_account").Inc()
+		middleware.AbortTooManyRequests(c, result.RetryAfter, i18n.MsgTooManyRequests)
+		return false
+	}
+	return true
+}
+
+// recordAccountFailure mencatat kegagalan untuk akun dan menghitung lockout yang terjadi
+func recordAccountFailure(c *gin.Context, scope, email string, policy ratelimit.LockoutPolicy) {
+	if ratelimit.RecordFailure(c.Request.Context(), scope, accountKey(email), policy) > 0 {
+		metrics.LockoutsTotal.WithLabelValues(scope).Inc()
+	}
+}
+
+// resetAccountFailures menghapus penghitung kegagalan akun setelah percobaan berhasil
+func resetAccountFailures(c *gin.Context, scope, email string) {
+	ratelimit.ResetFailures(c.Request.Context(), scope, accountKey(email))
+}
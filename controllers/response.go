@@ -0,0 +1,37 @@
+// controllers/response.go
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+)
+
+// t menerjemahkan kunci pesan ke bahasa request saat ini
+func t(c *gin.Context, key string, args ...interface{}) string {
+	return i18n.T(middleware.Language(c), key, args...)
+}
+
+// respondError mengirim ErrorResponse dengan pesan yang sudah diterjemahkan dan trace ID request
+func respondError(c *gin.Context, status int, key string, args ...interface{}) {
+	c.JSON(status, ErrorResponse{
+		Error:   t(c, key, args...),
+		TraceID: tracing.TraceID(c.Request.Context()),
+	})
+}
+
+// respondBindingError mengirim pesan error binding/validasi yang sudah diterjemahkan.
+// Body yang melebihi batas ukuran selalu dijawab dengan 413.
+func respondBindingError(c *gin.Context, status int, err error) {
+	if limit, exceeded := middleware.BodyLimitExceeded(err); exceeded {
+		respondError(c, http.StatusRequestEntityTooLarge, i18n.MsgRequestTooLarge, limit)
+		return
+	}
+	c.JSON(status, ErrorResponse{
+		Error:   i18n.TranslateBindingError(middleware.Language(c), err),
+		TraceID: tracing.TraceID(c.Request.Context()),
+	})
+}
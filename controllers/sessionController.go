@@ -0,0 +1,189 @@
+// controllers/sessionController.go
+package controllers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"gorm.io/gorm"
+)
+
+// DeviceNameHeader lets clients name the device, e.g. "Pixel 8"; otherwise it is derived from the User-Agent
+const DeviceNameHeader = "X-Device-Name"
+
+// SessionResponse represents an active session in the session list
+type SessionResponse struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+// GetSessions lists the active sessions of the authenticated user
+// @Summary List active sessions
+// @Description Returns the devices where the authenticated user is currently signed in. The session of the current token is marked with current=true.
+// @Tags Session
+// @Produce  json
+// @Success 200 {object} SuccessResponse{data=[]SessionResponse} "Active sessions"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/sessions [get]
+func GetSessions(c *gin.Context) {
+	userID, sessionID := c.GetUint(string(middleware.UserIDContextKey)), c.GetUint(string(middleware.SessionIDContextKey))
+
+	var sessions []models.Session
+	if err := activeSessions(config.DB.WithContext(c.Request.Context()), userID).Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	response := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, SessionResponse{Session: session, Current: session.ID == sessionID})
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgSessionsFetched),
+		Data:    response,
+	})
+}
+
+// RevokeSession ends one session of the authenticated user
+// @Summary End a session
+// @Description Revokes the given session so its token can no longer be used. Revoking the current session logs out.
+// @Tags Session
+// @Produce  json
+// @Param   id  path  int  true  "Session ID"
+// @Success 200 {object} SuccessResponse "Session ended"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Session not found"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/sessions/{id} [delete]
+func RevokeSession(c *gin.Context) {
+	userID := c.GetUint(string(middleware.UserIDContextKey))
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusNotFound, i18n.MsgSessionNotFound)
+		return
+	}
+
+	result := activeSessions(config.DB.WithContext(c.Request.Context()), userID).
+		Where("id = ?", id).Update("revoked_at", time.Now())
+	if result.Error != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondError(c, http.StatusNotFound, i18n.MsgSessionNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgSessionEnded),
+	})
+}
+
+// RevokeOtherSessions ends every session of the authenticated user except the current one
+// @Summary End all other sessions
+// @Description Revokes all sessions of the authenticated user except the one used for this request.
+// @Tags Session
+// @Produce  json
+// @Success 200 {object} SuccessResponse "Other sessions ended"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/sessions [delete]
+func RevokeOtherSessions(c *gin.Context) {
+	userID, sessionID := c.GetUint(string(middleware.UserIDContextKey)), c.GetUint(string(middleware.SessionIDContextKey))
+
+	revoked, err := revokeSessions(config.DB.WithContext(c.Request.Context()), userID, sessionID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgOtherSessionsEnded, revoked),
+		Data:    gin.H{"revoked": revoked},
+	})
+}
+
+// activeSessions scopes a query to the sessions of a user that are neither revoked nor expired
+func activeSessions(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now())
+}
+
+// revokeSessions revokes all active sessions of a user, optionally keeping one (keepID = 0 revokes all)
+func revokeSessions(db *gorm.DB, userID, keepID uint) (int64, error) {
+	query := activeSessions(db, userID)
+	if keepID != 0 {
+		query = query.Where("id <> ?", keepID)
+	}
+	result := query.Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// createSession records a new session for the device making the login request
+func createSession(c *gin.Context, user *models.User) (*models.Session, error) {
+	tokenID, err := utils.RandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+
+	// Values are sanitized before both the new-device lookup and the insert so that they compare equal
+	userAgent := utils.SanitizeHeaderValue(c.Request.UserAgent(), 512)
+	deviceName := utils.SanitizeHeaderValue(c.GetHeader(DeviceNameHeader), 100)
+	if deviceName == "" {
+		deviceName = utils.SanitizeHeaderValue(utils.DescribeUserAgent(userAgent), 100)
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		UserID:     user.ID,
+		TokenID:    tokenID,
+		DeviceName: deviceName,
+		UserAgent:  userAgent,
+		IPAddress:  c.ClientIP(),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(utils.AccessTokenTTL),
+	}
+
+	db := config.DB.WithContext(c.Request.Context())
+
+	// A device is new if the user has signed in before but never with this device
+	var previous, sameDevice int64
+	db.Model(&models.Session{}).Where("user_id = ?", user.ID).Count(&previous)
+	if previous > 0 {
+		db.Model(&models.Session{}).Where("user_id = ? AND user_agent = ? AND device_name = ?", user.ID, userAgent, deviceName).Count(&sameDevice)
+	}
+
+	if err := db.Create(session).Error; err != nil {
+		return nil, err
+	}
+
+	if previous > 0 && sameDevice == 0 && config.GetEnvBool("SESSION_NEW_DEVICE_EMAIL", true) {
+		notifyNewDevice(c, user, session)
+	}
+	return session, nil
+}
+
+// notifyNewDevice emails the user about a sign-in from an unknown device in the background
+func notifyNewDevice(c *gin.Context, user *models.User, session *models.Session) {
+	userID, email, language := user.ID, user.Email, user.Language
+	device, ipAddress, at := session.DeviceName, session.IPAddress, session.CreatedAt
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	services.StartWorker("new-device-email", func(context.Context) {
+		if err := utils.SendNewDeviceEmail(ctx, email, device, ipAddress, at, language); err != nil {
+			slog.ErrorContext(ctx, "Gagal mengirim notifikasi perangkat baru", "user_id", userID, "error", err)
+		}
+	})
+}
@@ -0,0 +1,354 @@
+// controllers/twoFactorController.go
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"gorm.io/gorm"
+)
+
+// totpCodePattern distinguishes authenticator codes from recovery codes
+var totpCodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// TwoFactorCodeRequest represents a request carrying an authenticator code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest represents the request body for disabling 2FA
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// TwoFactorLoginRequest represents the second step of a login with 2FA enabled
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Code is either a 6-digit authenticator code or a recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorSetupResponse contains the provisioning data for an authenticator app
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCode     string `json:"qr_code"`
+}
+
+// SetupTwoFactor starts TOTP enrollment for the authenticated user
+// @Summary Start two-factor authentication setup
+// @Description Generates a new TOTP secret and returns the otpauth:// provisioning URI and a QR code (PNG data URI). 2FA is only enabled after the code is confirmed.
+// @Tags 2FA
+// @Produce  json
+// @Success 200 {object} SuccessResponse{data=TwoFactorSetupResponse} "Provisioning data"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} ErrorResponse "Failed to set up two-factor authentication"
+// @Router  /api/users/2fa/setup [post]
+func SetupTwoFactor(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		respondError(c, http.StatusConflict, i18n.MsgTwoFactorAlreadyEnabled)
+		return
+	}
+
+	enrollment, err := utils.GenerateTOTP(config.GetEnv("TOTP_ISSUER", "Data Quota Tracker"), user.Email)
+	if err != nil {
+		metrics.TwoFactorEventsTotal.WithLabelValues("setup", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgTwoFactorSetupFailed)
+		return
+	}
+
+	// The secret is stored encrypted and stays inactive until confirmed with a valid code
+	encrypted, err := utils.EncryptSecret(enrollment.Secret)
+	if err != nil {
+		metrics.TwoFactorEventsTotal.WithLabelValues("setup", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgTwoFactorSetupFailed)
+		return
+	}
+	if err := config.DB.WithContext(c.Request.Context()).Model(&user).Updates(map[string]interface{}{
+		"two_factor_secret":    encrypted,
+		"two_factor_last_step": 0,
+	}).Error; err != nil {
+		metrics.TwoFactorEventsTotal.WithLabelValues("setup", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	metrics.TwoFactorEventsTotal.WithLabelValues("setup", "success").Inc()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgTwoFactorSetupStarted),
+		Data: TwoFactorSetupResponse{
+			Secret:     enrollment.Secret,
+			OTPAuthURL: enrollment.URL,
+			QRCode:     enrollment.QRCode,
+		},
+	})
+}
+
+// ConfirmTwoFactor enables 2FA after verifying a code from the authenticator app
+// @Summary Confirm two-factor authentication setup
+// @Description Verifies the first code from the authenticator app, enables 2FA and returns one-time recovery codes. The recovery codes are shown only once.
+// @Tags 2FA
+// @Accept  json
+// @Produce  json
+// @Param   code  body  TwoFactorCodeRequest  true  "Authenticator code"
+// @Success 200 {object} SuccessResponse "Recovery codes"
+// @Failure 400 {object} ErrorResponse "Invalid code or setup not started"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} ErrorResponse "Failed to set up two-factor authentication"
+// @Router  /api/users/2fa/confirm [post]
+func ConfirmTwoFactor(c *gin.Context) {
+	var input TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		respondError(c, http.StatusConflict, i18n.MsgTwoFactorAlreadyEnabled)
+		return
+	}
+	if user.TwoFactorSecret == "" {
+		respondError(c, http.StatusBadRequest, i18n.MsgTwoFactorSetupRequired)
+		return
+	}
+
+	secret, err := utils.DecryptSecret(user.TwoFactorSecret)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Gagal mendekripsi secret 2FA", "user_id", user.ID, "error", err)
+		respondError(c, http.StatusInternalServerError, i18n.MsgTwoFactorSetupFailed)
+		return
+	}
+
+	step, valid := utils.ValidateTOTP(secret, input.Code, user.TwoFactorLastStep)
+	if !valid {
+		metrics.TwoFactorEventsTotal.WithLabelValues("confirm", "invalid_code").Inc()
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidTwoFactorCode)
+		return
+	}
+
+	codes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		metrics.TwoFactorEventsTotal.WithLabelValues("confirm", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgTwoFactorSetupFailed)
+		return
+	}
+
+	err = config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled":   true,
+			"two_factor_last_step": step,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, user.ID, codes)
+	})
+	if err != nil {
+		metrics.TwoFactorEventsTotal.WithLabelValues("confirm", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	metrics.TwoFactorEventsTotal.WithLabelValues("confirm", "success").Inc()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgTwoFactorEnabled),
+		Data:    gin.H{"recovery_codes": codes},
+	})
+}
+
+// DisableTwoFactor turns off 2FA after re-checking the account password
+// @Summary Disable two-factor authentication
+// @Description Disables 2FA and deletes the remaining recovery codes. The current password is required.
+// @Tags 2FA
+// @Accept  json
+// @Produce  json
+// @Param   password  body  DisableTwoFactorRequest  true  "Current password"
+// @Success 200 {object} SuccessResponse "Two-factor authentication disabled"
+// @Failure 400 {object} ErrorResponse "Two-factor authentication is not enabled"
+// @Failure 401 {object} ErrorResponse "Unauthorized or invalid password"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/users/2fa/disable [post]
+func DisableTwoFactor(c *gin.Context) {
+	var input DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !user.TwoFactorEnabled && user.TwoFactorSecret == "" {
+		respondError(c, http.StatusBadRequest, i18n.MsgTwoFactorNotEnabled)
+		return
+	}
+
+	if !utils.CheckPasswordHash(input.Password, user.Password) {
+		metrics.TwoFactorEventsTotal.WithLabelValues("disable", "invalid_password").Inc()
+		respondError(c, http.StatusUnauthorized, i18n.MsgInvalidPassword)
+		return
+	}
+
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled":   false,
+			"two_factor_secret":    "",
+			"two_factor_last_step": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error
+	})
+	if err != nil {
+		metrics.TwoFactorEventsTotal.WithLabelValues("disable", "failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	metrics.TwoFactorEventsTotal.WithLabelValues("disable", "success").Inc()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgTwoFactorDisabled),
+	})
+}
+
+// LoginTwoFactor completes a login for accounts with 2FA enabled
+// @Summary Complete login with two-factor authentication
+// @Description Exchanges the challenge token returned by /auth/login and a 6-digit authenticator code (or a one-time recovery code) for a JWT token.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param   challenge  body  TwoFactorLoginRequest  true  "Challenge token and code"
+// @Success 200 {object} SuccessResponse "JWT token"
+// @Failure 400 {object} ErrorResponse "Invalid request payload"
+// @Failure 401 {object} ErrorResponse "Invalid challenge token or code"
+// @Failure 429 {object} ErrorResponse "Too many attempts or account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Error generating token or database error"
+// @Router  /auth/login/2fa [post]
+func LoginTwoFactor(c *gin.Context) {
+	var input TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	email, err := utils.ValidatePurposeToken(input.ChallengeToken, utils.Purpose2FA)
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, i18n.MsgInvalidChallengeToken)
+		return
+	}
+
+	if !guardAccount(c, "login_2fa", email, ratelimit.LoginPerAccount) {
+		metrics.LoginsTotal.WithLabelValues("rate_limited").Inc()
+		return
+	}
+
+	var user models.User
+	if err := config.DB.WithContext(c.Request.Context()).Where("email = ?", email).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			respondError(c, http.StatusUnauthorized, i18n.MsgInvalidChallengeToken)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		}
+		return
+	}
+	if !user.TwoFactorEnabled {
+		respondError(c, http.StatusUnauthorized, i18n.MsgInvalidChallengeToken)
+		return
+	}
+
+	valid, err := verifySecondFactor(c, &user, input.Code)
+	if err != nil {
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+	if !valid {
+		metrics.LoginsTotal.WithLabelValues("invalid_2fa_code").Inc()
+		recordAccountFailure(c, "login_2fa", email, ratelimit.LoginLockout)
+		respondError(c, http.StatusUnauthorized, i18n.MsgInvalidTwoFactorCode)
+		return
+	}
+	resetAccountFailures(c, "login_2fa", email)
+
+	completeLogin(c, &user)
+}
+
+// verifySecondFactor checks an authenticator code or consumes a recovery code
+func verifySecondFactor(c *gin.Context, user *models.User, code string) (bool, error) {
+	ctx := c.Request.Context()
+
+	if totpCodePattern.MatchString(code) {
+		secret, err := utils.DecryptSecret(user.TwoFactorSecret)
+		if err != nil {
+			return false, err
+		}
+		step, valid := utils.ValidateTOTP(secret, code, user.TwoFactorLastStep)
+		if !valid {
+			return false, nil
+		}
+
+		// Only advance the step if no concurrent request already used this code
+		result := config.DB.WithContext(ctx).Model(&models.User{}).
+			Where("id = ? AND two_factor_last_step < ?", user.ID, step).
+			Update("two_factor_last_step", step)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected == 1, nil
+	}
+
+	// Recovery codes are single-use: mark as used atomically so they cannot be replayed
+	now := time.Now()
+	result := config.DB.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.HashToken(utils.NormalizeRecoveryCode(code))).
+		Update("used_at", &now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	var remaining int64
+	config.DB.WithContext(ctx).Model(&models.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", user.ID).Count(&remaining)
+	metrics.TwoFactorEventsTotal.WithLabelValues("recovery_code", "success").Inc()
+	slog.InfoContext(ctx, "Recovery code digunakan untuk login", "user_id", user.ID, "remaining", remaining)
+	return true, nil
+}
+
+// replaceRecoveryCodes stores hashes of a fresh set of recovery codes, discarding the old ones
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	records := make([]models.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		records = append(records, models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(utils.NormalizeRecoveryCode(code)),
+		})
+	}
+	return tx.Create(&records).Error
+}
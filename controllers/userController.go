@@ -4,8 +4,10 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -14,8 +16,14 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
 	"github.com/mfuadfakhruzzaki/backend-api/middleware"
 	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -26,50 +34,50 @@ import (
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param        profile_picture formData file true "Profile Picture (JPG, JPEG, PNG)"
-// @Success      200  {object} gin.H{"message": "Profile picture uploaded successfully", "profile_picture": "url"}
-// @Failure      400  {object} gin.H{"error": "Error message"}
-// @Failure      401  {object} gin.H{"error": "Unauthorized message"}
-// @Failure      403  {object} gin.H{"error": "Forbidden message"}
-// @Failure      404  {object} gin.H{"error": "User not found"}
-// @Failure      500  {object} gin.H{"error": "Internal server error"}
+// @Success      200  {object} map[string]interface{} "Profile picture uploaded successfully"
+// @Failure      400  {object} ErrorResponse "Error message"
+// @Failure      401  {object} ErrorResponse "Unauthorized message"
+// @Failure      403  {object} ErrorResponse "Forbidden message"
+// @Failure      404  {object} ErrorResponse "User not found"
+// @Failure      500  {object} ErrorResponse "Internal server error"
 // @Router       /api/users/profile/picture [post]
 // UploadProfilePicture mengelola unggahan gambar profil pengguna
 func UploadProfilePicture(c *gin.Context) {
 	// Mengambil email pengguna dari context (ditetapkan oleh middleware JWT)
 	email, exists := c.Get(string(middleware.UserContextKey))
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak ditemukan dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailMissing)
 		return
 	}
 
 	emailStr, ok := email.(string)
 	if !ok || emailStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak valid dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailInvalid)
 		return
 	}
 
 	// Mencari pengguna di database berdasarkan email
 	var user models.User
-	result := config.DB.Where("email = ? AND deleted_at IS NULL", emailStr).First(&user)
+	result := config.DB.WithContext(c.Request.Context()).Where("email = ?", emailStr).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+			respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
 		}
 		return
 	}
 
 	// Memeriksa apakah email telah diverifikasi sebelum mengizinkan unggahan gambar profil
 	if !user.EmailVerified {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Email belum diverifikasi. Silakan verifikasi email Anda untuk mengunggah gambar profil."})
+		respondError(c, http.StatusForbidden, i18n.MsgEmailNotVerifiedUpload)
 		return
 	}
 
 	// Mengambil file dari form data dengan key "profile_picture"
 	fileHeader, err := c.FormFile("profile_picture")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Error retrieving file: %v", err)})
+		respondError(c, http.StatusBadRequest, i18n.MsgRetrieveFileFailed, err)
 		return
 	}
 
@@ -81,14 +89,14 @@ func UploadProfilePicture(c *gin.Context) {
 	}
 	fileExt := strings.ToLower(filepath.Ext(fileHeader.Filename))
 	if !allowedExtensions[fileExt] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Hanya JPG, JPEG, dan PNG yang diperbolehkan."})
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidFileType)
 		return
 	}
 
 	// Membuka file yang diunggah
 	uploadedFile, err := fileHeader.Open()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Error opening file: %v", err)})
+		respondError(c, http.StatusInternalServerError, i18n.MsgOpenFileFailed, err)
 		return
 	}
 	defer uploadedFile.Close()
@@ -97,7 +105,7 @@ func UploadProfilePicture(c *gin.Context) {
 	objectName := fmt.Sprintf("uploads/profile_pictures/user_%d%s", user.ID, fileExt)
 	bucketName := os.Getenv("GCS_BUCKET_NAME")
 	if bucketName == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Server configuration error: GCS_BUCKET_NAME tidak diatur"})
+		respondError(c, http.StatusInternalServerError, i18n.MsgStorageNotConfigured)
 		return
 	}
 
@@ -106,30 +114,32 @@ func UploadProfilePicture(c *gin.Context) {
 		parts := strings.Split(user.ProfilePicture, "/")
 		if len(parts) >= 5 {
 			objectNameOld := strings.Join(parts[4:], "/") // Sesuaikan berdasarkan struktur URL Anda
-			err := deleteFromCloudStorage(bucketName, objectNameOld)
+			err := services.DeleteFromCloudStorage(c.Request.Context(), bucketName, objectNameOld)
 			if err != nil {
 				// Mencatat error tetapi tidak mencegah unggahan
-				fmt.Printf("Gagal menghapus gambar profil lama: %v\n", err)
+				slog.WarnContext(c.Request.Context(), "Gagal menghapus gambar profil lama", "error", err)
 			}
 		}
 	}
 
 	// Mengunggah file ke Google Cloud Storage
-	if err := uploadToCloudStorage(bucketName, objectName, uploadedFile, fileExt); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Gagal mengunggah gambar profil ke cloud storage: %v", err)})
+	err = uploadToCloudStorage(c.Request.Context(), bucketName, objectName, uploadedFile, fileExt)
+	metrics.StorageUploadBytes.WithLabelValues("profile_picture", metrics.Result(err)).Observe(float64(fileHeader.Size))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgUploadPictureFailed, err)
 		return
 	}
 
 	// Memperbarui field ProfilePicture pengguna dengan URL baru di GCS
 	user.ProfilePicture = fmt.Sprintf("https://storage.googleapis.com/%s/%s", bucketName, objectName)
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating user profile"})
+	if err := config.DB.WithContext(c.Request.Context()).Save(&user).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgUpdateProfileFailed)
 		return
 	}
 
 	// Mengembalikan respons sukses
 	c.JSON(http.StatusOK, gin.H{
-		"message":         "Profile picture uploaded successfully",
+		"message":         t(c, i18n.MsgProfilePictureUploaded),
 		"profile_picture": user.ProfilePicture,
 	})
 }
@@ -140,34 +150,34 @@ func UploadProfilePicture(c *gin.Context) {
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Success      200  {object} gin.H{"message": "Profile fetched successfully", "profile": "Profile data"}
-// @Failure      401  {object} gin.H{"error": "Unauthorized message"}
-// @Failure      404  {object} gin.H{"error": "User not found"}
-// @Failure      500  {object} gin.H{"error": "Internal server error"}
+// @Success      200  {object} map[string]interface{} "Profile fetched successfully"
+// @Failure      401  {object} ErrorResponse "Unauthorized message"
+// @Failure      404  {object} ErrorResponse "User not found"
+// @Failure      500  {object} ErrorResponse "Internal server error"
 // @Router       /api/users/profile [get]
 // GetProfile mengambil dan mengembalikan profil pengguna yang sedang login
 func GetProfile(c *gin.Context) {
 	// Mengambil email pengguna dari context (ditetapkan oleh middleware JWT)
 	email, exists := c.Get(string(middleware.UserContextKey))
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak ditemukan dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailMissing)
 		return
 	}
 
 	emailStr, ok := email.(string)
 	if !ok || emailStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak valid dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailInvalid)
 		return
 	}
 
 	// Mencari pengguna di database berdasarkan email, preload relasi Package
 	var user models.User
-	result := config.DB.Preload("Package").Where("email = ? AND deleted_at IS NULL", emailStr).First(&user)
+	result := config.DB.WithContext(c.Request.Context()).Preload("Package").Where("email = ?", emailStr).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+			respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
 		}
 		return
 	}
@@ -184,31 +194,47 @@ func GetProfile(c *gin.Context) {
 		"email":           user.Email,
 		"username":        user.Username,
 		"phone_number":    user.PhoneNumber,
+		"phone_operator":  user.PhoneOperator,
+		"phone_verified":  user.PhoneVerified,
 		"profile_picture": user.ProfilePicture,
 		"package_id":      user.PackageID,
 		"package": gin.H{
-			"id":         user.Package.ID,
-			"name":       user.Package.Name,
-			"data":       user.Package.Data,
-			"duration":   user.Package.Duration,
-			"price":      user.Package.Price,
-			"details":    packageDetails,
-			"categories": user.Package.Categories,
-			"created_at": user.Package.CreatedAt,
-			"updated_at": user.Package.UpdatedAt,
+			"id":            user.Package.ID,
+			"name":          user.Package.Name,
+			"data":          user.Package.Data,
+			"duration":      user.Package.Duration,
+			"price":         user.Package.Price,
+			"currency":      user.Package.PriceMoney().Currency,
+			"price_display": user.Package.PriceMoney().Format(),
+			"details":       packageDetails,
+			"categories":    user.Package.Categories,
+			"created_at":    user.Package.CreatedAt,
+			"updated_at":    user.Package.UpdatedAt,
 		},
-		"email_verified": user.EmailVerified,
-		"created_at":     user.CreatedAt,
-		"updated_at":     user.UpdatedAt,
+		"email_verified":     user.EmailVerified,
+		"two_factor_enabled": user.TwoFactorEnabled,
+		"language":           user.Language,
+		"role":               user.Role,
+		"created_at":         user.CreatedAt,
+		"updated_at":         user.UpdatedAt,
 	}
 
 	// Mengembalikan respons sukses dengan data profil
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile fetched successfully",
+		"message": t(c, i18n.MsgProfileFetched),
 		"profile": profile,
 	})
 }
 
+// UpdateProfileInput represents the request body for updating the profile; omitted fields are left unchanged
+type UpdateProfileInput struct {
+	Email       *string `json:"email"`
+	Username    *string `json:"username"`
+	PhoneNumber *string `json:"phone_number"`
+	PackageID   *uint   `json:"package_id"`
+	Language    *string `json:"language" binding:"omitempty,oneof=id en"`
+}
+
 // UpdateProfile godoc
 // @Summary      Update User Profile
 // @Description  Update the profile information of the authenticated user
@@ -216,50 +242,42 @@ func GetProfile(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        profile body UpdateProfileInput true "Profile Information"
-// @Success      200  {object} gin.H{"message": "Profile updated successfully"}
-// @Failure      400  {object} gin.H{"error": "Error message"}
-// @Failure      401  {object} gin.H{"error": "Unauthorized message"}
-// @Failure      404  {object} gin.H{"error": "User not found"}
-// @Failure      500  {object} gin.H{"error": "Internal server error"}
+// @Success      200  {object} map[string]interface{} "Profile updated successfully"
+// @Failure      400  {object} ErrorResponse "Error message"
+// @Failure      401  {object} ErrorResponse "Unauthorized message"
+// @Failure      404  {object} ErrorResponse "User not found"
+// @Failure      500  {object} ErrorResponse "Internal server error"
 // @Router       /api/users/profile [put]
 // UpdateProfile mengupdate profil pengguna secara keseluruhan
 func UpdateProfile(c *gin.Context) {
 	// Mengambil email pengguna dari context (ditetapkan oleh middleware JWT)
 	email, exists := c.Get(string(middleware.UserContextKey))
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak ditemukan dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailMissing)
 		return
 	}
 
 	emailStr, ok := email.(string)
 	if !ok || emailStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak valid dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailInvalid)
 		return
 	}
 
 	// Mencari pengguna di database berdasarkan email
 	var user models.User
-	result := config.DB.Where("email = ? AND deleted_at IS NULL", emailStr).First(&user)
+	result := config.DB.WithContext(c.Request.Context()).Where("email = ?", emailStr).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+			respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
 		}
 		return
 	}
 
-	// Mendefinisikan struktur input untuk pembaruan
-	type UpdateProfileInput struct {
-		Email       *string `json:"email"`
-		Username    *string `json:"username"`
-		PhoneNumber *string `json:"phone_number"`
-		PackageID   *uint   `json:"package_id"`
-	}
-
 	var input UpdateProfileInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid input: %v", err)})
+		respondBindingError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -267,65 +285,86 @@ func UpdateProfile(c *gin.Context) {
 	updates := make(map[string]interface{})
 
 	// Memproses setiap field jika disediakan
-	if input.Email != nil {
-		updates["email"] = *input.Email
-	}
-
 	if input.Username != nil {
 		updates["username"] = *input.Username
 	}
 
+	// Nomor telepon adalah nomor line utama. String kosong menghapus line utama.
+	phone, operator := user.PhoneNumber, user.PhoneOperator
 	if input.PhoneNumber != nil {
-		updates["phone_number"] = *input.PhoneNumber
+		phone, operator = "", ""
+		if *input.PhoneNumber != "" {
+			var ok bool
+			if phone, operator, ok = normalizePhoneInput(c, *input.PhoneNumber, user.ID); !ok {
+				return
+			}
+		}
 	}
+	phoneChanged := phone != user.PhoneNumber
 
-	if input.PackageID != nil {
-		// Cek apakah PackageID valid (ada di database)
-		var pkg models.Package
-		if err := config.DB.Where("id = ?", *input.PackageID).First(&pkg).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Package ID tidak valid"})
-			return
-		}
-		updates["package_id"] = *input.PackageID
+	if input.Language != nil {
+		updates["language"] = *input.Language
 	}
 
-	// Validasi jika email diubah
-	if input.Email != nil && *input.Email != user.Email {
-		// Pastikan email belum digunakan oleh pengguna lain
-		var existingUser models.User
-		if err := config.DB.Where("email = ?", *input.Email).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Email sudah digunakan oleh pengguna lain"})
-			return
-		}
-		// Tandai email sebagai belum diverifikasi jika diubah
-		updates["email_verified"] = false
-		// Anda mungkin juga ingin mengirim email verifikasi baru di sini
+	// Paket hanya dapat dibeli melalui checkout (/api/packages/:id/select) agar langganan aktif setelah dibayar
+	if input.PackageID != nil && (user.PackageID == nil || *input.PackageID != *user.PackageID) {
+		respondError(c, http.StatusBadRequest, i18n.MsgPackageUseCheckout)
+		return
+	}
+
+	// Email hanya dapat diganti melalui /api/users/email agar alamat baru dikonfirmasi terlebih dahulu
+	if input.Email != nil && !strings.EqualFold(*input.Email, user.Email) {
+		respondError(c, http.StatusBadRequest, i18n.MsgEmailChangeUseEndpoint)
+		return
 	}
 
 	// Validasi jika username diubah
 	if input.Username != nil && *input.Username != user.Username {
 		// Pastikan username belum digunakan oleh pengguna lain
 		var existingUser models.User
-		if err := config.DB.Where("username = ?", *input.Username).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Username sudah digunakan oleh pengguna lain"})
+		if err := config.DB.WithContext(c.Request.Context()).Where("username = ?", *input.Username).First(&existingUser).Error; err == nil {
+			respondError(c, http.StatusBadRequest, i18n.MsgUsernameTaken)
 			return
 		}
 	}
 
-	// Memperbarui pengguna
-	if len(updates) > 0 {
-		if err := config.DB.Model(&user).Updates(updates).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating profile"})
-			return
+	// Memperbarui pengguna beserta line utama
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&user).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if phoneChanged {
+			if err := setPrimaryPhone(tx, &user, phone, operator); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errPrimaryLineInUse):
+		respondError(c, http.StatusBadRequest, i18n.MsgPrimaryLineInUse)
+		return
+	case isDuplicateKeyError(err):
+		respondError(c, http.StatusConflict, i18n.MsgPhoneNumberTaken)
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, i18n.MsgUpdateProfileFailed)
+		return
 	}
 
 	// Mengembalikan respons sukses
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile updated successfully",
+		"message": t(c, i18n.MsgProfileUpdated),
 	})
 }
 
+// UpdateUsernameInput represents the request body for changing the username
+type UpdateUsernameInput struct {
+	Username string `json:"username" binding:"required"`
+}
+
 // UpdateUsername godoc
 // @Summary      Update Username
 // @Description  Update the username of the authenticated user
@@ -333,70 +372,70 @@ func UpdateProfile(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        username body UpdateUsernameInput true "New Username"
-// @Success      200  {object} gin.H{"message": "Username updated successfully", "username": "new_username"}
-// @Failure      400  {object} gin.H{"error": "Error message"}
-// @Failure      401  {object} gin.H{"error": "Unauthorized message"}
-// @Failure      404  {object} gin.H{"error": "User not found"}
-// @Failure      500  {object} gin.H{"error": "Internal server error"}
+// @Success      200  {object} map[string]interface{} "Username updated successfully"
+// @Failure      400  {object} ErrorResponse "Error message"
+// @Failure      401  {object} ErrorResponse "Unauthorized message"
+// @Failure      404  {object} ErrorResponse "User not found"
+// @Failure      500  {object} ErrorResponse "Internal server error"
 // @Router       /api/users/profile/username [put]
 // UpdateUsername mengupdate username pengguna
 func UpdateUsername(c *gin.Context) {
 	// Mengambil email pengguna dari context (ditetapkan oleh middleware JWT)
 	email, exists := c.Get(string(middleware.UserContextKey))
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak ditemukan dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailMissing)
 		return
 	}
 
 	emailStr, ok := email.(string)
 	if !ok || emailStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak valid dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailInvalid)
 		return
 	}
 
 	// Mencari pengguna di database berdasarkan email
 	var user models.User
-	result := config.DB.Where("email = ? AND deleted_at IS NULL", emailStr).First(&user)
+	result := config.DB.WithContext(c.Request.Context()).Where("email = ?", emailStr).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+			respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
 		}
 		return
 	}
 
-	// Mendefinisikan struktur input untuk pembaruan username
-	type UpdateUsernameInput struct {
-		Username string `json:"username" binding:"required"`
-	}
-
 	var input UpdateUsernameInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid input: %v", err)})
+		respondBindingError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// Validasi apakah username sudah digunakan
 	var existingUser models.User
-	if err := config.DB.Where("username = ?", input.Username).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Username sudah digunakan oleh pengguna lain"})
+	if err := config.DB.WithContext(c.Request.Context()).Where("username = ?", input.Username).First(&existingUser).Error; err == nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgUsernameTaken)
 		return
 	}
 
 	// Memperbarui username
-	if err := config.DB.Model(&user).Update("username", input.Username).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating username"})
+	if err := config.DB.WithContext(c.Request.Context()).Model(&user).Update("username", input.Username).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgUpdateUsernameFailed)
 		return
 	}
 
 	// Mengembalikan respons sukses
 	c.JSON(http.StatusOK, gin.H{
-		"message":  "Username updated successfully",
+		"message":  t(c, i18n.MsgUsernameUpdated),
 		"username": input.Username,
 	})
 }
 
+// UpdatePhoneNumberInput represents the request body for changing the phone number
+type UpdatePhoneNumberInput struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+}
+
 // UpdatePhoneNumber godoc
 // @Summary      Update Phone Number
 // @Description  Update the phone number of the authenticated user
@@ -404,83 +443,103 @@ func UpdateUsername(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        phone_number body UpdatePhoneNumberInput true "New Phone Number"
-// @Success      200  {object} gin.H{"message": "Phone number updated successfully", "phone_number": "new_phone_number"}
-// @Failure      400  {object} gin.H{"error": "Error message"}
-// @Failure      401  {object} gin.H{"error": "Unauthorized message"}
-// @Failure      404  {object} gin.H{"error": "User not found"}
-// @Failure      500  {object} gin.H{"error": "Internal server error"}
+// @Success      200  {object} map[string]interface{} "Phone number updated successfully"
+// @Failure      400  {object} ErrorResponse "Error message"
+// @Failure      401  {object} ErrorResponse "Unauthorized message"
+// @Failure      404  {object} ErrorResponse "User not found"
+// @Failure      409  {object} ErrorResponse "Phone number already used"
+// @Failure      500  {object} ErrorResponse "Internal server error"
 // @Router       /api/users/profile/phone_number [put]
 // UpdatePhoneNumber mengupdate nomor telepon pengguna
 func UpdatePhoneNumber(c *gin.Context) {
 	// Mengambil email pengguna dari context (ditetapkan oleh middleware JWT)
 	email, exists := c.Get(string(middleware.UserContextKey))
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak ditemukan dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailMissing)
 		return
 	}
 
 	emailStr, ok := email.(string)
 	if !ok || emailStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Email tidak valid dalam context"})
+		respondError(c, http.StatusUnauthorized, i18n.MsgContextEmailInvalid)
 		return
 	}
 
 	// Mencari pengguna di database berdasarkan email
 	var user models.User
-	result := config.DB.Where("email = ? AND deleted_at IS NULL", emailStr).First(&user)
+	result := config.DB.WithContext(c.Request.Context()).Where("email = ?", emailStr).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+			respondError(c, http.StatusNotFound, i18n.MsgUserNotFound)
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
 		}
 		return
 	}
 
-	// Mendefinisikan struktur input untuk pembaruan nomor telepon
-	type UpdatePhoneNumberInput struct {
-		PhoneNumber string `json:"phone_number" binding:"required"`
-	}
-
 	var input UpdatePhoneNumberInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid input: %v", err)})
+		respondBindingError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	// Optional: Validasi format nomor telepon
-	// Misalnya, pastikan hanya angka dan panjang tertentu
-	if len(input.PhoneNumber) < 10 || len(input.PhoneNumber) > 15 || !isNumeric(input.PhoneNumber) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid phone number format"})
+	phone, operator, ok := normalizePhoneInput(c, input.PhoneNumber, user.ID)
+	if !ok {
 		return
 	}
 
-	// Memperbarui nomor telepon
-	if err := config.DB.Model(&user).Update("phone_number", input.PhoneNumber).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating phone number"})
+	// Memperbarui nomor line utama beserta operatornya; nomor baru harus diverifikasi ulang melalui OTP SMS
+	verified := user.PhoneVerified && phone == user.PhoneNumber
+	err := config.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		return setPrimaryPhone(tx, &user, phone, operator)
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			respondError(c, http.StatusConflict, i18n.MsgPhoneNumberTaken)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, i18n.MsgUpdatePhoneNumberFailed)
 		return
 	}
 
 	// Mengembalikan respons sukses
 	c.JSON(http.StatusOK, gin.H{
-		"message":      "Phone number updated successfully",
-		"phone_number": input.PhoneNumber,
+		"message":        t(c, i18n.MsgPhoneNumberUpdated),
+		"phone_number":   phone,
+		"phone_operator": operator,
+		"phone_verified": verified,
 	})
 }
 
-// Helper function untuk memeriksa apakah string hanya terdiri dari angka
-func isNumeric(s string) bool {
-	for _, c := range s {
-		if c < '0' || c > '9' {
-			return false
-		}
+// normalizePhoneInput mengubah nomor ke format E.164, mendeteksi operatornya, dan memastikan nomor
+// belum dipakai line milik akun lain. Respons error sudah dikirim jika ok bernilai false.
+func normalizePhoneInput(c *gin.Context, raw string, userID uint) (phone, operator string, ok bool) {
+	phone, err := utils.NormalizePhoneNumber(raw)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPhoneNumber)
+		return "", "", false
+	}
+
+	var count int64
+	if err := config.DB.WithContext(c.Request.Context()).Model(&models.Line{}).
+		Where("msisdn = ? AND user_id <> ?", phone, userID).Count(&count).Error; err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return "", "", false
 	}
-	return true
+	if count > 0 {
+		respondError(c, http.StatusConflict, i18n.MsgPhoneNumberTaken)
+		return "", "", false
+	}
+	return phone, utils.DetectOperator(phone), true
 }
 
 // uploadToCloudStorage mengunggah file ke Google Cloud Storage
-func uploadToCloudStorage(bucketName, objectName string, reader io.Reader, fileExt string) error {
-	ctx := context.Background()
+func uploadToCloudStorage(ctx context.Context, bucketName, objectName string, reader io.Reader, fileExt string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "storage.upload",
+		attribute.String("storage.bucket", bucketName),
+		attribute.String("storage.object", objectName),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
 
 	// Membuat klien Google Cloud Storage
 	client, err := storage.NewClient(ctx)
@@ -505,7 +564,9 @@ func uploadToCloudStorage(bucketName, objectName string, reader io.Reader, fileE
 	wc.ContentType = contentType
 
 	// Menyalin data file ke writer
-	if _, err := io.Copy(wc, reader); err != nil {
+	written, err := io.Copy(wc, reader)
+	span.SetAttributes(attribute.Int64("storage.bytes", written))
+	if err != nil {
 		return fmt.Errorf("gagal mengunggah file ke cloud storage: %v", err)
 	}
 
@@ -516,20 +577,3 @@ func uploadToCloudStorage(bucketName, objectName string, reader io.Reader, fileE
 
 	return nil
 }
-
-// deleteFromCloudStorage menghapus file dari Google Cloud Storage
-func deleteFromCloudStorage(bucketName, objectName string) error {
-	ctx := context.Background()
-	client, err := storage.NewClient(ctx)
-	if err != nil {
-		return fmt.Errorf("gagal membuat storage client: %v", err)
-	}
-	defer client.Close()
-
-	obj := client.Bucket(bucketName).Object(objectName)
-	if err := obj.Delete(ctx); err != nil {
-		return fmt.Errorf("gagal menghapus objek dari cloud storage: %v", err)
-	}
-
-	return nil
-}
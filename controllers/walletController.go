@@ -0,0 +1,179 @@
+// controllers/walletController.go
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+	"github.com/mfuadfakhruzzaki/backend-api/payment"
+	"github.com/mfuadfakhruzzaki/backend-api/wallet"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// TopUpRequest represents the request body for topping up the wallet
+type TopUpRequest struct {
+	// Amount in whole rupiah
+	Amount int64 `json:"amount" binding:"required"`
+}
+
+// PaginatedResponse wraps one page of a list
+type PaginatedResponse struct {
+	Items    interface{} `json:"items"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Total    int64       `json:"total"`
+}
+
+// GetWallet returns the wallet balance of the authenticated user
+// @Summary Get wallet balance
+// @Description The balance is derived from the entries of the wallet account in the ledger.
+// @Tags Wallet
+// @Produce  json
+// @Success 200 {object} SuccessResponse "Wallet balance"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/wallet [get]
+func GetWallet(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	respondWalletBalance(c, user.ID)
+}
+
+// GetWalletTransactions returns the wallet transaction history of the authenticated user, newest first
+// @Summary List wallet transactions
+// @Description Returns one page of wallet transactions (top-ups, purchases, refunds and adjustments) with the balance after each transaction.
+// @Tags Wallet
+// @Produce  json
+// @Param   page       query  int  false  "Page number (default 1)"
+// @Param   page_size  query  int  false  "Items per page (default 20, max 100)"
+// @Success 200 {object} SuccessResponse{data=PaginatedResponse} "Wallet transactions"
+// @Failure 400 {object} ErrorResponse "Invalid pagination"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Router  /api/wallet/transactions [get]
+func GetWalletTransactions(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	respondWalletHistory(c, user.ID)
+}
+
+// TopUpWallet creates a top-up order and starts its payment
+// @Summary Top up the wallet
+// @Description Creates a pending top-up order and starts its payment at the payment gateway. The balance is added when the gateway confirms the payment.
+// @Tags Wallet
+// @Accept  json
+// @Produce  json
+// @Param   request  body  TopUpRequest  true  "Top-up amount"
+// @Success 201 {object} SuccessResponse{data=models.Order} "Top-up order created, includes the payment URL"
+// @Failure 400 {object} ErrorResponse "Amount out of range"
+// @Failure 500 {object} ErrorResponse "Database error"
+// @Failure 502 {object} ErrorResponse "Payment gateway error"
+// @Router  /api/wallet/topups [post]
+func TopUpWallet(c *gin.Context) {
+	var input TopUpRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	minAmount := money.Rupiah(int64(config.GetEnvInt("WALLET_TOPUP_MIN", 10000)))
+	maxAmount := money.Rupiah(int64(config.GetEnvInt("WALLET_TOPUP_MAX", 2000000)))
+	if input.Amount < minAmount.Amount || input.Amount > maxAmount.Amount {
+		respondError(c, http.StatusBadRequest, i18n.MsgTopUpAmountOutOfRange, minAmount.Format(), maxAmount.Format())
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	order, err := newOrder(models.OrderTypeTopUp, &user, payment.Default().Name())
+	if err == nil {
+		order.Amount, order.OriginalAmount, order.Currency = input.Amount, input.Amount, money.IDR
+		err = config.DB.WithContext(c.Request.Context()).Create(&order).Error
+	}
+	if err == nil {
+		err = startPayment(c, &user, &order, "Top-up "+order.AmountMoney().Format(), user.PhoneNumber)
+	}
+	if err != nil {
+		if errors.Is(err, errPaymentGateway) {
+			respondError(c, http.StatusBadGateway, i18n.MsgPaymentGatewayFailed)
+		} else {
+			respondError(c, http.StatusInternalServerError, i18n.MsgCreateOrderFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: t(c, i18n.MsgTopUpCreated),
+		Data:    order,
+	})
+}
+
+// respondWalletBalance sends the wallet balance of the user
+func respondWalletBalance(c *gin.Context, userID uint) {
+	balance, err := wallet.UserBalance(config.DB.WithContext(c.Request.Context()), userID, money.IDR)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgWalletFetched),
+		Data: gin.H{
+			"balance":         balance.Amount,
+			"currency":        balance.Currency,
+			"balance_display": balance.Format(),
+		},
+	})
+}
+
+// respondWalletHistory sends the page of wallet transactions of the user selected with ?page= and ?page_size=
+func respondWalletHistory(c *gin.Context, userID uint) {
+	page, pageSize, ok := paginationFromQuery(c)
+	if !ok {
+		return
+	}
+
+	transactions, total, err := wallet.History(config.DB.WithContext(c.Request.Context()), userID, money.IDR, pageSize, (page-1)*pageSize)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, i18n.MsgDatabaseError)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: t(c, i18n.MsgWalletTransactionsFetched),
+		Data:    PaginatedResponse{Items: transactions, Page: page, PageSize: pageSize, Total: total},
+	})
+}
+
+// paginationFromQuery reads ?page= (default 1) and ?page_size= (default 20, capped at 100)
+func paginationFromQuery(c *gin.Context) (page, pageSize int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPagination)
+		return 0, 0, false
+	}
+	pageSize, err = strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
+	if err != nil || pageSize < 1 {
+		respondError(c, http.StatusBadRequest, i18n.MsgInvalidPagination)
+		return 0, 0, false
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize, true
+}
@@ -0,0 +1,76 @@
+// i18n/i18n.go
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Bahasa yang didukung oleh API
+const (
+	LangID = "id"
+	LangEN = "en"
+)
+
+// SupportedLanguages berisi daftar bahasa yang memiliki katalog pesan
+var SupportedLanguages = []string{LangID, LangEN}
+
+var matcher = language.NewMatcher([]language.Tag{language.Indonesian, language.English})
+
+// DefaultLanguage mengembalikan bahasa bawaan dari DEFAULT_LANGUAGE, atau "id" jika tidak diatur
+func DefaultLanguage() string {
+	if lang := Normalize(os.Getenv("DEFAULT_LANGUAGE")); lang != "" {
+		return lang
+	}
+	return LangID
+}
+
+// Normalize mengubah kode bahasa (misalnya "en-US" atau "ID") menjadi kode yang didukung,
+// atau string kosong jika bahasa tersebut tidak didukung
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	for _, supported := range SupportedLanguages {
+		if lang == supported {
+			return lang
+		}
+	}
+	return ""
+}
+
+// ParseAcceptLanguage memilih bahasa terbaik dari header Accept-Language
+func ParseAcceptLanguage(header string) string {
+	if strings.TrimSpace(header) == "" {
+		return DefaultLanguage()
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage()
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLanguage()
+	}
+	return SupportedLanguages[index]
+}
+
+// T menerjemahkan kunci pesan ke bahasa yang diminta. Argumen tambahan diformat dengan fmt.Sprintf.
+// Jika pesan tidak ada dalam bahasa tersebut, katalog bahasa Inggris digunakan, lalu kunci itu sendiri.
+func T(lang, key string, args ...interface{}) string {
+	format, ok := messages[lang][key]
+	if !ok {
+		format, ok = messages[LangEN][key]
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(format, args...)
+	}
+	return format
+}
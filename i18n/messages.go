@@ -0,0 +1,679 @@
+// i18n/messages.go
+package i18n
+
+// Kunci pesan yang digunakan di seluruh API
+const (
+	// Umum
+	MsgInvalidRequestPayload = "invalid_request_payload"
+	MsgInvalidInput          = "invalid_input"
+	MsgDatabaseError         = "database_error"
+	MsgUnsupportedLanguage   = "unsupported_language"
+	MsgTooManyRequests       = "too_many_requests"
+	MsgRequestTooLarge       = "request_too_large"
+
+	// Autentikasi
+	MsgAuthHeaderMissing       = "auth_header_missing"
+	MsgAuthHeaderInvalidFormat = "auth_header_invalid_format"
+	MsgInvalidToken            = "invalid_token"
+	MsgContextEmailMissing     = "context_email_missing"
+	MsgContextEmailInvalid     = "context_email_invalid"
+	MsgPasswordEmpty           = "password_empty"
+	MsgHashPasswordFailed      = "hash_password_failed"
+	MsgVerificationCodeFailed  = "verification_code_failed"
+	MsgEmailOrUsernameExists   = "email_or_username_exists"
+	MsgCreateUserFailed        = "create_user_failed"
+	MsgSendVerificationFailed  = "send_verification_email_failed"
+	MsgRegistrationSuccess     = "registration_success"
+	MsgUserNotFound            = "user_not_found"
+	MsgInvalidVerificationCode = "invalid_verification_code"
+	MsgVerifyEmailFailed       = "verify_email_failed"
+	MsgEmailVerified           = "email_verified"
+	MsgEmailNotVerified        = "email_not_verified"
+	MsgInvalidPassword         = "invalid_password"
+	MsgInvalidCredentials      = "invalid_credentials"
+	MsgAccountLocked           = "account_locked"
+	MsgGenerateTokenFailed     = "generate_token_failed"
+	MsgLoginSuccess            = "login_success"
+
+	// Password
+	MsgPasswordTooShort             = "password_too_short"
+	MsgPasswordTooLong              = "password_too_long"
+	MsgPasswordNeedsUpper           = "password_needs_upper"
+	MsgPasswordNeedsLower           = "password_needs_lower"
+	MsgPasswordNeedsDigit           = "password_needs_digit"
+	MsgPasswordNeedsSymbol          = "password_needs_symbol"
+	MsgPasswordContainsPersonalInfo = "password_contains_personal_info"
+	MsgPasswordBreached             = "password_breached"
+	MsgPasswordUnchanged            = "password_unchanged"
+	MsgPasswordChanged              = "password_changed"
+
+	// Autentikasi dua faktor
+	MsgTwoFactorRequired       = "two_factor_required"
+	MsgTwoFactorAlreadyEnabled = "two_factor_already_enabled"
+	MsgTwoFactorNotEnabled     = "two_factor_not_enabled"
+	MsgTwoFactorSetupRequired  = "two_factor_setup_required"
+	MsgTwoFactorSetupFailed    = "two_factor_setup_failed"
+	MsgTwoFactorSetupStarted   = "two_factor_setup_started"
+	MsgTwoFactorEnabled        = "two_factor_enabled"
+	MsgTwoFactorDisabled       = "two_factor_disabled"
+	MsgInvalidTwoFactorCode    = "invalid_two_factor_code"
+	MsgInvalidChallengeToken   = "invalid_challenge_token"
+
+	// Login dengan provider OpenID Connect
+	MsgOIDCProviderNotFound  = "oidc_provider_not_found"
+	MsgOIDCAuthFailed        = "oidc_auth_failed"
+	MsgOIDCInvalidState      = "oidc_invalid_state"
+	MsgOIDCEmailNotVerified  = "oidc_email_not_verified"
+	MsgOIDCLinkRequired      = "oidc_link_required"
+	MsgInvalidLinkToken      = "invalid_link_token"
+	MsgIdentityLinked        = "identity_linked"
+	MsgIdentityAlreadyLinked = "identity_already_linked"
+	MsgIdentitiesFetched     = "identities_fetched"
+	MsgIdentityNotFound      = "identity_not_found"
+	MsgIdentityUnlinked      = "identity_unlinked"
+	MsgCannotUnlinkLastLogin = "cannot_unlink_last_login"
+
+	// Login tanpa password
+	MsgPasswordlessDisabled    = "passwordless_disabled"
+	MsgLoginCodeSent           = "login_code_sent"
+	MsgLoginCodeFailed         = "login_code_failed"
+	MsgInvalidLoginCode        = "invalid_login_code"
+	MsgLoginCodeRequestInvalid = "login_code_request_invalid"
+	MsgLoginCodeEmailSubject   = "login_code_email_subject"
+	MsgLoginCodeEmailBody      = "login_code_email_body"
+	MsgLoginCodeEmailLink      = "login_code_email_link"
+
+	// Sesi dan perangkat
+	MsgSessionRevoked        = "session_revoked"
+	MsgCreateSessionFailed   = "create_session_failed"
+	MsgSessionsFetched       = "sessions_fetched"
+	MsgSessionNotFound       = "session_not_found"
+	MsgSessionEnded          = "session_ended"
+	MsgOtherSessionsEnded    = "other_sessions_ended"
+	MsgNewDeviceEmailSubject = "new_device_email_subject"
+	MsgNewDeviceEmailBody    = "new_device_email_body"
+
+	// Penggantian email
+	MsgEmailChangeUseEndpoint    = "email_change_use_endpoint"
+	MsgEmailUnchanged            = "email_unchanged"
+	MsgEmailChangeFailed         = "email_change_failed"
+	MsgEmailChangeRequested      = "email_change_requested"
+	MsgInvalidEmailChangeCode    = "invalid_email_change_code"
+	MsgEmailChanged              = "email_changed"
+	MsgInvalidUndoToken          = "invalid_undo_token"
+	MsgEmailChangeUndone         = "email_change_undone"
+	MsgEmailChangeCodeSubject    = "email_change_code_subject"
+	MsgEmailChangeCodeBody       = "email_change_code_body"
+	MsgEmailChangedNoticeSubject = "email_changed_notice_subject"
+	MsgEmailChangedNoticeBody    = "email_changed_notice_body"
+
+	// Penghapusan akun dan ekspor data pribadi
+	MsgAccountDeleted              = "account_deleted"
+	MsgDeleteAccountFailed         = "delete_account_failed"
+	MsgDeleteAccountWalletNotEmpty = "delete_account_wallet_not_empty"
+	MsgAccountDeletedEmailSubject  = "account_deleted_email_subject"
+	MsgAccountDeletedEmailBody     = "account_deleted_email_body"
+	MsgDataExportFailed            = "data_export_failed"
+
+	// Paket
+	MsgFetchPackagesFailed     = "fetch_packages_failed"
+	MsgFetchPackageFailed      = "fetch_package_failed"
+	MsgInvalidPackageID        = "invalid_package_id"
+	MsgPackageNotFound         = "package_not_found"
+	MsgUpdateUserPackageFailed = "update_user_package_failed"
+	MsgPackageSelected         = "package_selected"
+	MsgInvalidOperator         = "invalid_operator"
+	MsgPhoneOperatorUnknown    = "phone_operator_unknown"
+
+	// Profil pengguna
+	MsgEmailNotVerifiedUpload  = "email_not_verified_upload"
+	MsgRetrieveFileFailed      = "retrieve_file_failed"
+	MsgInvalidFileType         = "invalid_file_type"
+	MsgOpenFileFailed          = "open_file_failed"
+	MsgStorageNotConfigured    = "storage_not_configured"
+	MsgUploadPictureFailed     = "upload_picture_failed"
+	MsgUpdateProfileFailed     = "update_profile_failed"
+	MsgProfilePictureUploaded  = "profile_picture_uploaded"
+	MsgProfileFetched          = "profile_fetched"
+	MsgProfileUpdated          = "profile_updated"
+	MsgEmailTaken              = "email_taken"
+	MsgUsernameTaken           = "username_taken"
+	MsgUpdateUsernameFailed    = "update_username_failed"
+	MsgUsernameUpdated         = "username_updated"
+	MsgInvalidPhoneNumber      = "invalid_phone_number"
+	MsgUpdatePhoneNumberFailed = "update_phone_number_failed"
+	MsgPhoneNumberUpdated      = "phone_number_updated"
+	MsgPhoneNumberTaken        = "phone_number_taken"
+
+	// Verifikasi nomor telepon (OTP SMS)
+	MsgPhoneNumberRequired          = "phone_number_required"
+	MsgPhoneAlreadyVerified         = "phone_already_verified"
+	MsgPhoneVerificationFailed      = "phone_verification_failed"
+	MsgSendSMSFailed                = "send_sms_failed"
+	MsgPhoneVerificationSent        = "phone_verification_sent"
+	MsgInvalidPhoneVerificationCode = "invalid_phone_verification_code"
+	MsgPhoneVerified                = "phone_verified"
+	MsgPhoneNotVerified             = "phone_not_verified"
+	MsgPhoneVerificationSMS         = "phone_verification_sms"
+
+	// Line (nomor seluler milik pengguna)
+	MsgLinesFetched       = "lines_fetched"
+	MsgLineAdded          = "line_added"
+	MsgLineUpdated        = "line_updated"
+	MsgLineDeleted        = "line_deleted"
+	MsgPrimaryLineChanged = "primary_line_changed"
+	MsgPrimaryLineInUse   = "primary_line_in_use"
+	MsgLineLimitReached   = "line_limit_reached"
+	MsgInvalidLineID      = "invalid_line_id"
+	MsgLineNotFound       = "line_not_found"
+
+	// Order dan pembayaran
+	MsgPackageUseCheckout           = "package_use_checkout"
+	MsgOrderCreated                 = "order_created"
+	MsgCreateOrderFailed            = "create_order_failed"
+	MsgPaymentGatewayFailed         = "payment_gateway_failed"
+	MsgOrdersFetched                = "orders_fetched"
+	MsgOrderFetched                 = "order_fetched"
+	MsgInvalidOrderID               = "invalid_order_id"
+	MsgOrderNotFound                = "order_not_found"
+	MsgPaymentGatewayNotFound       = "payment_gateway_not_found"
+	MsgInvalidPaymentSignature      = "invalid_payment_signature"
+	MsgInvalidPaymentNotification   = "invalid_payment_notification"
+	MsgPaymentAmountMismatch        = "payment_amount_mismatch"
+	MsgPaymentNotificationProcessed = "payment_notification_processed"
+
+	// Invoice dan tanda terima
+	MsgInvoicesFetched      = "invoices_fetched"
+	MsgInvalidInvoiceID     = "invalid_invoice_id"
+	MsgInvoiceNotFound      = "invoice_not_found"
+	MsgInvoiceRenderFailed  = "invoice_render_failed"
+	MsgReceiptEmailSubject  = "receipt_email_subject"
+	MsgReceiptEmailBody     = "receipt_email_body"
+	MsgInvoiceTitle         = "invoice_title"
+	MsgInvoiceNumberLabel   = "invoice_number_label"
+	MsgInvoiceDateLabel     = "invoice_date_label"
+	MsgInvoiceOrderLabel    = "invoice_order_label"
+	MsgInvoiceStatusPaid    = "invoice_status_paid"
+	MsgInvoiceTaxIDLabel    = "invoice_tax_id_label"
+	MsgInvoiceBilledTo      = "invoice_billed_to"
+	MsgInvoiceDescription   = "invoice_description"
+	MsgInvoiceAmountLabel   = "invoice_amount_label"
+	MsgInvoiceDiscountLabel = "invoice_discount_label"
+	MsgInvoiceSubtotalLabel = "invoice_subtotal_label"
+	MsgInvoiceTaxLabel      = "invoice_tax_label"
+	MsgInvoiceTotalLabel    = "invoice_total_label"
+	MsgInvoiceFooter        = "invoice_footer"
+
+	// Wallet dan admin
+	MsgWalletFetched              = "wallet_fetched"
+	MsgWalletTransactionsFetched  = "wallet_transactions_fetched"
+	MsgInvalidPagination          = "invalid_pagination"
+	MsgInsufficientBalance        = "insufficient_balance"
+	MsgInvalidPaymentMethod       = "invalid_payment_method"
+	MsgPackagePurchasedWithWallet = "package_purchased_with_wallet"
+	MsgTopUpAmountOutOfRange      = "topup_amount_out_of_range"
+	MsgTopUpCreated               = "topup_created"
+	MsgWalletTransactionFailed    = "wallet_transaction_failed"
+	MsgAdminRequired              = "admin_required"
+	MsgInvalidUserID              = "invalid_user_id"
+	MsgOrderNotRefundable         = "order_not_refundable"
+	MsgOrderRefunded              = "order_refunded"
+	MsgInvalidAdjustmentAmount    = "invalid_adjustment_amount"
+	MsgWalletAdjusted             = "wallet_adjusted"
+	MsgVerificationEmailSubject   = "verification_email_subject"
+	MsgVerificationEmailBody      = "verification_email_body"
+
+	// Kode promo
+	MsgQuoteFetched        = "quote_fetched"
+	MsgPromoNotFound       = "promo_not_found"
+	MsgPromoNotActive      = "promo_not_active"
+	MsgPromoUsageLimit     = "promo_usage_limit"
+	MsgPromoUserUsageLimit = "promo_user_usage_limit"
+	MsgPromoNotEligible    = "promo_not_eligible"
+	MsgPromoNotStackable   = "promo_not_stackable"
+	MsgTooManyPromoCodes   = "too_many_promo_codes"
+	MsgPromosFetched       = "promos_fetched"
+	MsgPromoCreated        = "promo_created"
+	MsgPromoUpdated        = "promo_updated"
+	MsgPromoCodeTaken      = "promo_code_taken"
+	MsgInvalidPromo        = "invalid_promo"
+	MsgInvalidPromoID      = "invalid_promo_id"
+)
+
+// messages adalah katalog terjemahan per bahasa
+var messages = map[string]map[string]string{
+	LangEN: {
+		MsgInvalidRequestPayload: "Invalid request payload",
+		MsgInvalidInput:          "Invalid input: %s",
+		MsgDatabaseError:         "Database error",
+		MsgUnsupportedLanguage:   "Unsupported language. Supported languages: %s",
+		MsgTooManyRequests:       "Too many requests. Please try again in %d seconds.",
+		MsgRequestTooLarge:       "Request body is too large. Maximum size is %d bytes.",
+
+		MsgAuthHeaderMissing:       "Authorization header missing",
+		MsgAuthHeaderInvalidFormat: "Invalid Authorization header format. Expected 'Bearer <token>'",
+		MsgInvalidToken:            "Invalid or expired token",
+		MsgContextEmailMissing:     "Unauthorized: user email not found in context",
+		MsgContextEmailInvalid:     "Unauthorized: invalid user email in context",
+		MsgPasswordEmpty:           "Password cannot be empty",
+		MsgHashPasswordFailed:      "Error hashing password",
+		MsgVerificationCodeFailed:  "Error generating verification code",
+		MsgEmailOrUsernameExists:   "Email, username or phone number already exists",
+		MsgCreateUserFailed:        "Error creating user",
+		MsgSendVerificationFailed:  "Failed to send verification email",
+		MsgRegistrationSuccess:     "Registration successful! Please check your email to verify your account.",
+		MsgUserNotFound:            "User not found",
+		MsgInvalidVerificationCode: "Invalid verification code",
+		MsgVerifyEmailFailed:       "Failed to verify email",
+		MsgEmailVerified:           "Email verified successfully!",
+		MsgEmailNotVerified:        "Email not verified. Please verify your email first.",
+		MsgInvalidPassword:         "Invalid password",
+		MsgInvalidCredentials:      "Invalid email or password",
+		MsgAccountLocked:           "Too many failed attempts. Please try again in %d seconds.",
+		MsgGenerateTokenFailed:     "Error generating token",
+		MsgLoginSuccess:            "Login successful",
+
+		MsgPasswordTooShort:             "Password must be at least %d characters long",
+		MsgPasswordTooLong:              "Password must be at most %d bytes long",
+		MsgPasswordNeedsUpper:           "Password must contain an uppercase letter",
+		MsgPasswordNeedsLower:           "Password must contain a lowercase letter",
+		MsgPasswordNeedsDigit:           "Password must contain a digit",
+		MsgPasswordNeedsSymbol:          "Password must contain a symbol",
+		MsgPasswordContainsPersonalInfo: "Password must not contain your email or username",
+		MsgPasswordBreached:             "This password has appeared in a data breach. Please choose a different password.",
+		MsgPasswordUnchanged:            "New password must be different from the current password",
+		MsgPasswordChanged:              "Password changed successfully. You have been signed out on other devices.",
+
+		MsgTwoFactorRequired:       "Two-factor authentication required. Enter the code from your authenticator app.",
+		MsgTwoFactorAlreadyEnabled: "Two-factor authentication is already enabled",
+		MsgTwoFactorNotEnabled:     "Two-factor authentication is not enabled",
+		MsgTwoFactorSetupRequired:  "Start two-factor authentication setup first",
+		MsgTwoFactorSetupFailed:    "Failed to set up two-factor authentication",
+		MsgTwoFactorSetupStarted:   "Scan the QR code with your authenticator app, then confirm with a code",
+		MsgTwoFactorEnabled:        "Two-factor authentication enabled. Store your recovery codes in a safe place.",
+		MsgTwoFactorDisabled:       "Two-factor authentication disabled",
+		MsgInvalidTwoFactorCode:    "Invalid two-factor authentication code",
+		MsgInvalidChallengeToken:   "Invalid or expired login challenge. Please log in again.",
+
+		MsgOIDCProviderNotFound:  "Unknown sign-in provider",
+		MsgOIDCAuthFailed:        "Sign-in with the provider failed. Please try again.",
+		MsgOIDCInvalidState:      "Invalid or expired sign-in session. Please try again.",
+		MsgOIDCEmailNotVerified:  "The provider has not verified your email address",
+		MsgOIDCLinkRequired:      "An account with this email already exists. Confirm your password to link it.",
+		MsgInvalidLinkToken:      "Invalid or expired link request. Please sign in with the provider again.",
+		MsgIdentityLinked:        "Account linked successfully",
+		MsgIdentityAlreadyLinked: "This provider account is already linked to another user",
+		MsgIdentitiesFetched:     "Linked accounts fetched successfully",
+		MsgIdentityNotFound:      "Linked account not found",
+		MsgIdentityUnlinked:      "Account unlinked successfully",
+		MsgCannotUnlinkLastLogin: "Cannot unlink the only sign-in method. Set a password first.",
+
+		MsgPasswordlessDisabled:    "Passwordless login is not enabled",
+		MsgLoginCodeSent:           "If an account exists for this email, a login code has been sent.",
+		MsgLoginCodeFailed:         "Failed to create login code",
+		MsgInvalidLoginCode:        "Invalid or expired login code",
+		MsgLoginCodeRequestInvalid: "Provide either email and code, or token",
+		MsgLoginCodeEmailSubject:   "Your Data Quota Tracker login code",
+		MsgLoginCodeEmailBody:      "Your login code is: %s\n\nThe code is valid for %d minutes and can only be used once. If you did not request it, you can ignore this email.",
+		MsgLoginCodeEmailLink:      "Or sign in directly with this link:\n%s",
+
+		MsgSessionRevoked:        "Your session has ended. Please log in again.",
+		MsgCreateSessionFailed:   "Failed to create session",
+		MsgSessionsFetched:       "Active sessions fetched successfully",
+		MsgSessionNotFound:       "Session not found",
+		MsgSessionEnded:          "Session ended successfully",
+		MsgOtherSessionsEnded:    "%d other session(s) ended",
+		MsgNewDeviceEmailSubject: "New sign-in to your Data Quota Tracker account",
+		MsgNewDeviceEmailBody:    "Your account was just signed in from a new device.\n\nDevice: %s\nIP address: %s\nTime: %s\n\nIf this was you, no action is needed. If not, end the session from the app and change your password immediately.",
+
+		MsgEmailChangeUseEndpoint:    "Email cannot be changed here. Use POST /api/users/email to change it with a confirmation code.",
+		MsgEmailUnchanged:            "New email must be different from the current email",
+		MsgEmailChangeFailed:         "Failed to start the email change",
+		MsgEmailChangeRequested:      "A confirmation code has been sent to %s. Your email will change once you confirm it.",
+		MsgInvalidEmailChangeCode:    "Invalid or expired confirmation code",
+		MsgEmailChanged:              "Email changed successfully",
+		MsgInvalidUndoToken:          "Invalid or expired undo link",
+		MsgEmailChangeUndone:         "The email change has been undone and all sessions have been ended. Please log in again and change your password.",
+		MsgEmailChangeCodeSubject:    "Confirm your new Data Quota Tracker email",
+		MsgEmailChangeCodeBody:       "Your confirmation code is: %s\n\nEnter it in the app within %d minutes to use this address for your account. If you did not request it, you can ignore this email.",
+		MsgEmailChangedNoticeSubject: "Your Data Quota Tracker email was changed",
+		MsgEmailChangedNoticeBody:    "The email address of your account was changed to %s.\n\nIf you did not make this change, undo it within %d hours:\n%s",
+
+		MsgAccountDeleted:              "Your account has been deleted. Your personal data will be permanently removed after %d days.",
+		MsgDeleteAccountFailed:         "Failed to delete account",
+		MsgDeleteAccountWalletNotEmpty: "Your wallet still has a balance of %s. Spend it or contact support for a refund before deleting your account.",
+		MsgAccountDeletedEmailSubject:  "Your Data Quota Tracker account has been deleted",
+		MsgAccountDeletedEmailBody:     "Your account has been deleted and you have been signed out on all devices.\n\nYour personal data will be permanently removed after %d days. If you did not request this, contact support before then to restore your account.",
+		MsgDataExportFailed:            "Failed to export your data",
+
+		MsgFetchPackagesFailed:     "Error fetching packages",
+		MsgFetchPackageFailed:      "Error fetching package",
+		MsgInvalidPackageID:        "Invalid package ID",
+		MsgPackageNotFound:         "Package not found",
+		MsgUpdateUserPackageFailed: "Error updating user package",
+		MsgPackageSelected:         "Package selected successfully",
+		MsgInvalidOperator:         "Unknown operator. Supported operators: %s, or mine",
+		MsgPhoneOperatorUnknown:    "The operator of your phone number is unknown. Add a valid phone number to your profile first",
+
+		MsgEmailNotVerifiedUpload:  "Email not verified. Please verify your email to upload a profile picture.",
+		MsgRetrieveFileFailed:      "Error retrieving file: %v",
+		MsgInvalidFileType:         "Invalid file type. Only JPG, JPEG and PNG are allowed.",
+		MsgOpenFileFailed:          "Error opening file: %v",
+		MsgStorageNotConfigured:    "Server configuration error: GCS_BUCKET_NAME is not set",
+		MsgUploadPictureFailed:     "Failed to upload profile picture to cloud storage: %v",
+		MsgUpdateProfileFailed:     "Error updating profile",
+		MsgProfilePictureUploaded:  "Profile picture uploaded successfully",
+		MsgProfileFetched:          "Profile fetched successfully",
+		MsgProfileUpdated:          "Profile updated successfully",
+		MsgEmailTaken:              "Email is already used by another user",
+		MsgUsernameTaken:           "Username is already used by another user",
+		MsgUpdateUsernameFailed:    "Error updating username",
+		MsgUsernameUpdated:         "Username updated successfully",
+		MsgInvalidPhoneNumber:      "Invalid phone number. Use an Indonesian mobile number such as 081234567890 or +6281234567890",
+		MsgUpdatePhoneNumberFailed: "Error updating phone number",
+		MsgPhoneNumberUpdated:      "Phone number updated successfully",
+		MsgPhoneNumberTaken:        "Phone number is already used by another user",
+
+		MsgPhoneNumberRequired:          "Add a phone number to your profile first",
+		MsgPhoneAlreadyVerified:         "Phone number is already verified",
+		MsgPhoneVerificationFailed:      "Failed to start phone number verification",
+		MsgSendSMSFailed:                "Failed to send SMS",
+		MsgPhoneVerificationSent:        "A verification code has been sent by SMS to %s",
+		MsgInvalidPhoneVerificationCode: "Invalid or expired verification code",
+		MsgPhoneVerified:                "Phone number verified successfully",
+		MsgPhoneNotVerified:             "Verify the phone number of the line before selecting a package",
+		MsgPhoneVerificationSMS:         "Your Data Quota Tracker verification code is %s. It expires in %d minutes. Never share this code with anyone.",
+
+		MsgLinesFetched:       "Lines fetched successfully",
+		MsgLineAdded:          "Line added. Verify it by SMS before selecting a package",
+		MsgLineUpdated:        "Line updated successfully",
+		MsgLineDeleted:        "Line removed successfully",
+		MsgPrimaryLineChanged: "Primary line changed successfully",
+		MsgPrimaryLineInUse:   "The primary line cannot be removed while you have other lines. Make another line primary first",
+		MsgLineLimitReached:   "You can have at most %d lines",
+		MsgInvalidLineID:      "Invalid line ID",
+		MsgLineNotFound:       "Line not found",
+
+		MsgPackageUseCheckout:           "Packages can only be bought through /api/packages/:id/select",
+		MsgOrderCreated:                 "Order created. Complete the payment to activate the package",
+		MsgCreateOrderFailed:            "Failed to create order",
+		MsgPaymentGatewayFailed:         "The payment gateway is unavailable. Please try again later",
+		MsgOrdersFetched:                "Orders fetched successfully",
+		MsgOrderFetched:                 "Order fetched successfully",
+		MsgInvalidOrderID:               "Invalid order ID",
+		MsgOrderNotFound:                "Order not found",
+		MsgPaymentGatewayNotFound:       "Unknown payment gateway",
+		MsgInvalidPaymentSignature:      "Invalid payment notification signature",
+		MsgInvalidPaymentNotification:   "Invalid payment notification",
+		MsgPaymentAmountMismatch:        "Paid amount does not match the order",
+		MsgPaymentNotificationProcessed: "Payment notification processed",
+		MsgInvoicesFetched:              "Invoices fetched successfully",
+		MsgInvalidInvoiceID:             "Invalid invoice ID",
+		MsgInvoiceNotFound:              "Invoice not found",
+		MsgInvoiceRenderFailed:          "Failed to generate the invoice PDF",
+		MsgReceiptEmailSubject:          "Payment receipt %s",
+		MsgReceiptEmailBody:             "Thank you for your purchase. We have received your payment of %s for %s.\n\nYour invoice %s is attached to this email and can also be downloaded from the app at any time.",
+		MsgInvoiceTitle:                 "INVOICE",
+		MsgInvoiceNumberLabel:           "Invoice number",
+		MsgInvoiceDateLabel:             "Date",
+		MsgInvoiceOrderLabel:            "Order",
+		MsgInvoiceStatusPaid:            "PAID",
+		MsgInvoiceTaxIDLabel:            "Tax ID (NPWP)",
+		MsgInvoiceBilledTo:              "Billed to",
+		MsgInvoiceDescription:           "Description",
+		MsgInvoiceAmountLabel:           "Amount",
+		MsgInvoiceDiscountLabel:         "Discount (%s)",
+		MsgInvoiceSubtotalLabel:         "Subtotal (tax base)",
+		MsgInvoiceTaxLabel:              "VAT (PPN) %s",
+		MsgInvoiceTotalLabel:            "Total paid",
+		MsgInvoiceFooter:                "Prices include VAT. This invoice was generated electronically and is valid without a signature.",
+		MsgWalletFetched:                "Wallet balance fetched successfully",
+		MsgWalletTransactionsFetched:    "Wallet transactions fetched successfully",
+		MsgInvalidPagination:            "page and page_size must be positive integers",
+		MsgInsufficientBalance:          "Insufficient wallet balance",
+		MsgInvalidPaymentMethod:         "Invalid payment method: %s",
+		MsgPackagePurchasedWithWallet:   "Package purchased with your wallet balance",
+		MsgTopUpAmountOutOfRange:        "Top-up amount must be between %s and %s",
+		MsgTopUpCreated:                 "Top-up created. Complete the payment to add the balance",
+		MsgWalletTransactionFailed:      "Failed to process the wallet transaction",
+		MsgAdminRequired:                "Admin access required",
+		MsgInvalidUserID:                "Invalid user ID",
+		MsgOrderNotRefundable:           "Only paid package orders can be refunded",
+		MsgOrderRefunded:                "Order refunded to the wallet balance",
+		MsgInvalidAdjustmentAmount:      "Adjustment amount must not be zero",
+		MsgWalletAdjusted:               "Wallet balance adjusted",
+		MsgQuoteFetched:                 "Price quote calculated",
+		MsgPromoNotFound:                "Promo code %s not found",
+		MsgPromoNotActive:               "Promo code %s is not active",
+		MsgPromoUsageLimit:              "Promo code %s has been fully redeemed",
+		MsgPromoUserUsageLimit:          "You have reached the usage limit of promo code %s",
+		MsgPromoNotEligible:             "Promo code %s cannot be used for this package",
+		MsgPromoNotStackable:            "Promo code %s cannot be combined with other codes",
+		MsgTooManyPromoCodes:            "At most %d promo codes can be used per purchase",
+		MsgPromosFetched:                "Promo codes fetched successfully",
+		MsgPromoCreated:                 "Promo code created",
+		MsgPromoUpdated:                 "Promo code updated",
+		MsgPromoCodeTaken:               "Promo code already exists",
+		MsgInvalidPromo:                 "Invalid promo: %s",
+		MsgInvalidPromoID:               "Invalid promo code ID",
+		MsgVerificationEmailSubject:     "Email Verification for Data Quota Tracker",
+		MsgVerificationEmailBody:        "Welcome to Data Quota Tracker!\n\nYour verification code is: %s\n\nPlease enter this code to verify your email and start using the app.",
+	},
+	LangID: {
+		MsgInvalidRequestPayload: "Payload permintaan tidak valid",
+		MsgInvalidInput:          "Input tidak valid: %s",
+		MsgDatabaseError:         "Terjadi kesalahan database",
+		MsgUnsupportedLanguage:   "Bahasa tidak didukung. Bahasa yang didukung: %s",
+		MsgTooManyRequests:       "Terlalu banyak permintaan. Silakan coba lagi dalam %d detik.",
+		MsgRequestTooLarge:       "Body permintaan terlalu besar. Ukuran maksimum %d byte.",
+
+		MsgAuthHeaderMissing:       "Header Authorization tidak ditemukan",
+		MsgAuthHeaderInvalidFormat: "Format header Authorization tidak valid. Gunakan 'Bearer <token>'",
+		MsgInvalidToken:            "Token tidak valid atau sudah kedaluwarsa",
+		MsgContextEmailMissing:     "Unauthorized: Email tidak ditemukan dalam context",
+		MsgContextEmailInvalid:     "Unauthorized: Email tidak valid dalam context",
+		MsgPasswordEmpty:           "Password tidak boleh kosong",
+		MsgHashPasswordFailed:      "Gagal memproses password",
+		MsgVerificationCodeFailed:  "Gagal membuat kode verifikasi",
+		MsgEmailOrUsernameExists:   "Email, username, atau nomor telepon sudah terdaftar",
+		MsgCreateUserFailed:        "Gagal membuat pengguna",
+		MsgSendVerificationFailed:  "Gagal mengirim email verifikasi",
+		MsgRegistrationSuccess:     "Registrasi berhasil! Silakan cek email Anda untuk memverifikasi akun.",
+		MsgUserNotFound:            "User tidak ditemukan",
+		MsgInvalidVerificationCode: "Kode verifikasi tidak valid",
+		MsgVerifyEmailFailed:       "Gagal memverifikasi email",
+		MsgEmailVerified:           "Email berhasil diverifikasi!",
+		MsgEmailNotVerified:        "Email belum diverifikasi. Silakan verifikasi email Anda terlebih dahulu.",
+		MsgInvalidPassword:         "Password salah",
+		MsgInvalidCredentials:      "Email atau password salah",
+		MsgAccountLocked:           "Terlalu banyak percobaan gagal. Silakan coba lagi dalam %d detik.",
+		MsgGenerateTokenFailed:     "Gagal membuat token",
+		MsgLoginSuccess:            "Login berhasil",
+
+		MsgPasswordTooShort:             "Password minimal %d karakter",
+		MsgPasswordTooLong:              "Password maksimal %d byte",
+		MsgPasswordNeedsUpper:           "Password harus mengandung huruf besar",
+		MsgPasswordNeedsLower:           "Password harus mengandung huruf kecil",
+		MsgPasswordNeedsDigit:           "Password harus mengandung angka",
+		MsgPasswordNeedsSymbol:          "Password harus mengandung simbol",
+		MsgPasswordContainsPersonalInfo: "Password tidak boleh memuat email atau username Anda",
+		MsgPasswordBreached:             "Password ini pernah muncul dalam kebocoran data. Silakan pilih password lain.",
+		MsgPasswordUnchanged:            "Password baru harus berbeda dari password saat ini",
+		MsgPasswordChanged:              "Password berhasil diubah. Anda telah dikeluarkan dari perangkat lain.",
+
+		MsgTwoFactorRequired:       "Autentikasi dua faktor diperlukan. Masukkan kode dari aplikasi authenticator Anda.",
+		MsgTwoFactorAlreadyEnabled: "Autentikasi dua faktor sudah aktif",
+		MsgTwoFactorNotEnabled:     "Autentikasi dua faktor belum aktif",
+		MsgTwoFactorSetupRequired:  "Mulai pengaturan autentikasi dua faktor terlebih dahulu",
+		MsgTwoFactorSetupFailed:    "Gagal mengatur autentikasi dua faktor",
+		MsgTwoFactorSetupStarted:   "Pindai kode QR dengan aplikasi authenticator Anda, lalu konfirmasi dengan kode",
+		MsgTwoFactorEnabled:        "Autentikasi dua faktor diaktifkan. Simpan recovery code Anda di tempat yang aman.",
+		MsgTwoFactorDisabled:       "Autentikasi dua faktor dinonaktifkan",
+		MsgInvalidTwoFactorCode:    "Kode autentikasi dua faktor tidak valid",
+		MsgInvalidChallengeToken:   "Tantangan login tidak valid atau kedaluwarsa. Silakan login kembali.",
+
+		MsgOIDCProviderNotFound:  "Provider login tidak dikenal",
+		MsgOIDCAuthFailed:        "Login dengan provider gagal. Silakan coba lagi.",
+		MsgOIDCInvalidState:      "Sesi login tidak valid atau kedaluwarsa. Silakan coba lagi.",
+		MsgOIDCEmailNotVerified:  "Provider belum memverifikasi alamat email Anda",
+		MsgOIDCLinkRequired:      "Akun dengan email ini sudah ada. Konfirmasi password Anda untuk menautkannya.",
+		MsgInvalidLinkToken:      "Permintaan penautan tidak valid atau kedaluwarsa. Silakan login dengan provider kembali.",
+		MsgIdentityLinked:        "Akun berhasil ditautkan",
+		MsgIdentityAlreadyLinked: "Akun provider ini sudah ditautkan ke pengguna lain",
+		MsgIdentitiesFetched:     "Daftar akun tertaut berhasil diambil",
+		MsgIdentityNotFound:      "Akun tertaut tidak ditemukan",
+		MsgIdentityUnlinked:      "Tautan akun berhasil dihapus",
+		MsgCannotUnlinkLastLogin: "Tidak dapat menghapus satu-satunya metode login. Atur password terlebih dahulu.",
+
+		MsgPasswordlessDisabled:    "Login tanpa password tidak diaktifkan",
+		MsgLoginCodeSent:           "Jika akun dengan email ini terdaftar, kode login telah dikirim.",
+		MsgLoginCodeFailed:         "Gagal membuat kode login",
+		MsgInvalidLoginCode:        "Kode login tidak valid atau sudah kedaluwarsa",
+		MsgLoginCodeRequestInvalid: "Kirim email dan kode, atau token",
+		MsgLoginCodeEmailSubject:   "Kode login Data Quota Tracker Anda",
+		MsgLoginCodeEmailBody:      "Kode login Anda adalah: %s\n\nKode berlaku selama %d menit dan hanya dapat digunakan sekali. Abaikan email ini jika Anda tidak memintanya.",
+		MsgLoginCodeEmailLink:      "Atau login langsung melalui tautan berikut:\n%s",
+
+		MsgSessionRevoked:        "Sesi Anda telah berakhir. Silakan login kembali.",
+		MsgCreateSessionFailed:   "Gagal membuat sesi",
+		MsgSessionsFetched:       "Daftar sesi aktif berhasil diambil",
+		MsgSessionNotFound:       "Sesi tidak ditemukan",
+		MsgSessionEnded:          "Sesi berhasil diakhiri",
+		MsgOtherSessionsEnded:    "%d sesi lain berhasil diakhiri",
+		MsgNewDeviceEmailSubject: "Login baru ke akun Data Quota Tracker Anda",
+		MsgNewDeviceEmailBody:    "Akun Anda baru saja digunakan untuk login dari perangkat baru.\n\nPerangkat: %s\nAlamat IP: %s\nWaktu: %s\n\nJika ini Anda, tidak ada yang perlu dilakukan. Jika bukan, akhiri sesi tersebut dari aplikasi dan segera ganti password Anda.",
+
+		MsgEmailChangeUseEndpoint:    "Email tidak dapat diubah di sini. Gunakan POST /api/users/email untuk menggantinya dengan kode konfirmasi.",
+		MsgEmailUnchanged:            "Email baru harus berbeda dari email saat ini",
+		MsgEmailChangeFailed:         "Gagal memulai penggantian email",
+		MsgEmailChangeRequested:      "Kode konfirmasi telah dikirim ke %s. Email Anda akan diganti setelah dikonfirmasi.",
+		MsgInvalidEmailChangeCode:    "Kode konfirmasi tidak valid atau sudah kedaluwarsa",
+		MsgEmailChanged:              "Email berhasil diganti",
+		MsgInvalidUndoToken:          "Tautan pembatalan tidak valid atau sudah kedaluwarsa",
+		MsgEmailChangeUndone:         "Penggantian email telah dibatalkan dan semua sesi diakhiri. Silakan login kembali dan ganti password Anda.",
+		MsgEmailChangeCodeSubject:    "Konfirmasi email baru Data Quota Tracker Anda",
+		MsgEmailChangeCodeBody:       "Kode konfirmasi Anda adalah: %s\n\nMasukkan kode ini di aplikasi dalam %d menit untuk menggunakan alamat ini pada akun Anda. Abaikan email ini jika Anda tidak memintanya.",
+		MsgEmailChangedNoticeSubject: "Email akun Data Quota Tracker Anda telah diganti",
+		MsgEmailChangedNoticeBody:    "Alamat email akun Anda telah diganti menjadi %s.\n\nJika Anda tidak melakukan perubahan ini, batalkan dalam %d jam:\n%s",
+
+		MsgAccountDeleted:              "Akun Anda telah dihapus. Data pribadi Anda akan dihapus permanen setelah %d hari.",
+		MsgDeleteAccountFailed:         "Gagal menghapus akun",
+		MsgDeleteAccountWalletNotEmpty: "Wallet Anda masih memiliki saldo %s. Gunakan saldo tersebut atau hubungi dukungan untuk refund sebelum menghapus akun.",
+		MsgAccountDeletedEmailSubject:  "Akun Data Quota Tracker Anda telah dihapus",
+		MsgAccountDeletedEmailBody:     "Akun Anda telah dihapus dan Anda telah dikeluarkan dari semua perangkat.\n\nData pribadi Anda akan dihapus permanen setelah %d hari. Jika Anda tidak memintanya, hubungi dukungan sebelum waktu tersebut untuk memulihkan akun Anda.",
+		MsgDataExportFailed:            "Gagal mengekspor data Anda",
+
+		MsgFetchPackagesFailed:     "Gagal mengambil daftar paket",
+		MsgFetchPackageFailed:      "Gagal mengambil paket",
+		MsgInvalidPackageID:        "ID paket tidak valid",
+		MsgPackageNotFound:         "Paket tidak ditemukan",
+		MsgUpdateUserPackageFailed: "Gagal memperbarui paket pengguna",
+		MsgPackageSelected:         "Paket berhasil dipilih",
+		MsgInvalidOperator:         "Operator tidak dikenal. Operator yang didukung: %s, atau mine",
+		MsgPhoneOperatorUnknown:    "Operator nomor telepon Anda tidak diketahui. Tambahkan nomor telepon yang valid ke profil terlebih dahulu",
+
+		MsgEmailNotVerifiedUpload:  "Email belum diverifikasi. Silakan verifikasi email Anda untuk mengunggah gambar profil.",
+		MsgRetrieveFileFailed:      "Gagal mengambil file: %v",
+		MsgInvalidFileType:         "Tipe file tidak valid. Hanya JPG, JPEG, dan PNG yang diperbolehkan.",
+		MsgOpenFileFailed:          "Gagal membuka file: %v",
+		MsgStorageNotConfigured:    "Kesalahan konfigurasi server: GCS_BUCKET_NAME tidak diatur",
+		MsgUploadPictureFailed:     "Gagal mengunggah gambar profil ke cloud storage: %v",
+		MsgUpdateProfileFailed:     "Gagal memperbarui profil",
+		MsgProfilePictureUploaded:  "Gambar profil berhasil diunggah",
+		MsgProfileFetched:          "Profil berhasil diambil",
+		MsgProfileUpdated:          "Profil berhasil diperbarui",
+		MsgEmailTaken:              "Email sudah digunakan oleh pengguna lain",
+		MsgUsernameTaken:           "Username sudah digunakan oleh pengguna lain",
+		MsgUpdateUsernameFailed:    "Gagal memperbarui username",
+		MsgUsernameUpdated:         "Username berhasil diperbarui",
+		MsgInvalidPhoneNumber:      "Nomor telepon tidak valid. Gunakan nomor seluler Indonesia seperti 081234567890 atau +6281234567890",
+		MsgUpdatePhoneNumberFailed: "Gagal memperbarui nomor telepon",
+		MsgPhoneNumberUpdated:      "Nomor telepon berhasil diperbarui",
+		MsgPhoneNumberTaken:        "Nomor telepon sudah digunakan oleh pengguna lain",
+
+		MsgPhoneNumberRequired:          "Tambahkan nomor telepon ke profil terlebih dahulu",
+		MsgPhoneAlreadyVerified:         "Nomor telepon sudah terverifikasi",
+		MsgPhoneVerificationFailed:      "Gagal memulai verifikasi nomor telepon",
+		MsgSendSMSFailed:                "Gagal mengirim SMS",
+		MsgPhoneVerificationSent:        "Kode verifikasi telah dikirim melalui SMS ke %s",
+		MsgInvalidPhoneVerificationCode: "Kode verifikasi tidak valid atau sudah kedaluwarsa",
+		MsgPhoneVerified:                "Nomor telepon berhasil diverifikasi",
+		MsgPhoneNotVerified:             "Verifikasi nomor telepon pada line tersebut sebelum memilih paket",
+		MsgPhoneVerificationSMS:         "Kode verifikasi Data Quota Tracker Anda adalah %s. Berlaku %d menit. Jangan berikan kode ini kepada siapa pun.",
+
+		MsgLinesFetched:       "Daftar nomor berhasil diambil",
+		MsgLineAdded:          "Nomor ditambahkan. Verifikasi melalui SMS sebelum memilih paket",
+		MsgLineUpdated:        "Nomor berhasil diperbarui",
+		MsgLineDeleted:        "Nomor berhasil dihapus",
+		MsgPrimaryLineChanged: "Nomor utama berhasil diganti",
+		MsgPrimaryLineInUse:   "Nomor utama tidak dapat dihapus selama masih ada nomor lain. Jadikan nomor lain sebagai nomor utama terlebih dahulu",
+		MsgLineLimitReached:   "Anda hanya dapat memiliki maksimal %d nomor",
+		MsgInvalidLineID:      "ID nomor tidak valid",
+		MsgLineNotFound:       "Nomor tidak ditemukan",
+
+		MsgPackageUseCheckout:           "Paket hanya dapat dibeli melalui /api/packages/:id/select",
+		MsgOrderCreated:                 "Order dibuat. Selesaikan pembayaran untuk mengaktifkan paket",
+		MsgCreateOrderFailed:            "Gagal membuat order",
+		MsgPaymentGatewayFailed:         "Payment gateway sedang tidak tersedia. Silakan coba lagi nanti",
+		MsgOrdersFetched:                "Daftar order berhasil diambil",
+		MsgOrderFetched:                 "Order berhasil diambil",
+		MsgInvalidOrderID:               "ID order tidak valid",
+		MsgOrderNotFound:                "Order tidak ditemukan",
+		MsgPaymentGatewayNotFound:       "Payment gateway tidak dikenal",
+		MsgInvalidPaymentSignature:      "Tanda tangan notifikasi pembayaran tidak valid",
+		MsgInvalidPaymentNotification:   "Notifikasi pembayaran tidak valid",
+		MsgPaymentAmountMismatch:        "Nominal pembayaran tidak sesuai dengan order",
+		MsgPaymentNotificationProcessed: "Notifikasi pembayaran diproses",
+		MsgInvoicesFetched:              "Daftar invoice berhasil diambil",
+		MsgInvalidInvoiceID:             "ID invoice tidak valid",
+		MsgInvoiceNotFound:              "Invoice tidak ditemukan",
+		MsgInvoiceRenderFailed:          "Gagal membuat PDF invoice",
+		MsgReceiptEmailSubject:          "Tanda terima pembayaran %s",
+		MsgReceiptEmailBody:             "Terima kasih atas pembelian Anda. Pembayaran sebesar %s untuk %s telah kami terima.\n\nInvoice %s terlampir pada email ini dan dapat diunduh kembali dari aplikasi kapan saja.",
+		MsgInvoiceTitle:                 "INVOICE",
+		MsgInvoiceNumberLabel:           "Nomor invoice",
+		MsgInvoiceDateLabel:             "Tanggal",
+		MsgInvoiceOrderLabel:            "Order",
+		MsgInvoiceStatusPaid:            "LUNAS",
+		MsgInvoiceTaxIDLabel:            "NPWP",
+		MsgInvoiceBilledTo:              "Ditagihkan kepada",
+		MsgInvoiceDescription:           "Deskripsi",
+		MsgInvoiceAmountLabel:           "Jumlah",
+		MsgInvoiceDiscountLabel:         "Diskon (%s)",
+		MsgInvoiceSubtotalLabel:         "Dasar pengenaan pajak",
+		MsgInvoiceTaxLabel:              "PPN %s",
+		MsgInvoiceTotalLabel:            "Total dibayar",
+		MsgInvoiceFooter:                "Harga sudah termasuk PPN. Invoice ini dibuat secara elektronik dan sah tanpa tanda tangan.",
+		MsgWalletFetched:                "Saldo wallet berhasil diambil",
+		MsgWalletTransactionsFetched:    "Riwayat transaksi wallet berhasil diambil",
+		MsgInvalidPagination:            "page dan page_size harus berupa bilangan bulat positif",
+		MsgInsufficientBalance:          "Saldo wallet tidak cukup",
+		MsgInvalidPaymentMethod:         "Metode pembayaran tidak valid: %s",
+		MsgPackagePurchasedWithWallet:   "Paket berhasil dibeli dengan saldo wallet",
+		MsgTopUpAmountOutOfRange:        "Nominal top-up harus antara %s dan %s",
+		MsgTopUpCreated:                 "Top-up dibuat. Selesaikan pembayaran untuk menambah saldo",
+		MsgWalletTransactionFailed:      "Gagal memproses transaksi wallet",
+		MsgAdminRequired:                "Akses admin diperlukan",
+		MsgInvalidUserID:                "ID user tidak valid",
+		MsgOrderNotRefundable:           "Hanya order paket yang sudah lunas yang dapat direfund",
+		MsgOrderRefunded:                "Order direfund ke saldo wallet",
+		MsgInvalidAdjustmentAmount:      "Nominal penyesuaian tidak boleh nol",
+		MsgWalletAdjusted:               "Saldo wallet disesuaikan",
+		MsgQuoteFetched:                 "Harga paket berhasil dihitung",
+		MsgPromoNotFound:                "Kode promo %s tidak ditemukan",
+		MsgPromoNotActive:               "Kode promo %s sedang tidak berlaku",
+		MsgPromoUsageLimit:              "Kuota kode promo %s sudah habis",
+		MsgPromoUserUsageLimit:          "Anda sudah mencapai batas pemakaian kode promo %s",
+		MsgPromoNotEligible:             "Kode promo %s tidak berlaku untuk paket ini",
+		MsgPromoNotStackable:            "Kode promo %s tidak dapat digabung dengan kode lain",
+		MsgTooManyPromoCodes:            "Maksimal %d kode promo dalam satu pembelian",
+		MsgPromosFetched:                "Daftar kode promo berhasil diambil",
+		MsgPromoCreated:                 "Kode promo berhasil dibuat",
+		MsgPromoUpdated:                 "Kode promo berhasil diperbarui",
+		MsgPromoCodeTaken:               "Kode promo sudah digunakan",
+		MsgInvalidPromo:                 "Kode promo tidak valid: %s",
+		MsgInvalidPromoID:               "ID kode promo tidak valid",
+		MsgVerificationEmailSubject:     "Verifikasi Email Data Quota Tracker",
+		MsgVerificationEmailBody:        "Selamat datang di Data Quota Tracker!\n\nKode verifikasi Anda adalah: %s\n\nMasukkan kode ini untuk memverifikasi email Anda dan mulai menggunakan aplikasi.",
+	},
+}
@@ -0,0 +1,65 @@
+// i18n/validation.go
+package i18n
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+var universalTranslator *ut.UniversalTranslator
+
+// RegisterValidatorTranslations mendaftarkan terjemahan pesan validasi ke validator milik gin.
+// Nama field pada pesan diambil dari tag json agar sesuai dengan payload yang dikirim klien.
+func RegisterValidatorTranslations() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		slog.Warn("Validator gin tidak dikenali, terjemahan validasi dilewati")
+		return
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	enLocale := en.New()
+	universalTranslator = ut.New(enLocale, enLocale, id.New())
+
+	enTrans, _ := universalTranslator.GetTranslator(LangEN)
+	if err := enTranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		slog.Warn("Gagal mendaftarkan terjemahan validasi", "lang", LangEN, "error", err)
+	}
+	idTrans, _ := universalTranslator.GetTranslator(LangID)
+	if err := idTranslations.RegisterDefaultTranslations(validate, idTrans); err != nil {
+		slog.Warn("Gagal mendaftarkan terjemahan validasi", "lang", LangID, "error", err)
+	}
+}
+
+// TranslateBindingError mengubah error dari ShouldBind* menjadi pesan yang dapat dibaca pengguna.
+// Error validasi diterjemahkan per field, sedangkan error lain (misalnya JSON rusak) menjadi pesan umum.
+func TranslateBindingError(lang string, err error) string {
+	var validationErrors validator.ValidationErrors
+	if universalTranslator == nil || !errors.As(err, &validationErrors) {
+		return T(lang, MsgInvalidRequestPayload)
+	}
+
+	trans, _ := universalTranslator.GetTranslator(lang)
+	details := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		details = append(details, fieldErr.Translate(trans))
+	}
+	return T(lang, MsgInvalidInput, strings.Join(details, "; "))
+}
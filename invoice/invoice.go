@@ -0,0 +1,141 @@
+// invoice/invoice.go
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+	"gorm.io/gorm"
+)
+
+// Settings berisi identitas penjual dan tarif pajak yang dicetak pada invoice baru
+type Settings struct {
+	// TaxRateBasisPoints adalah tarif PPN dalam basis point (1100 = 11%)
+	TaxRateBasisPoints int64
+	NumberPrefix       string
+	SellerName         string
+	SellerAddress      string
+	SellerTaxID        string
+}
+
+var settings = Settings{TaxRateBasisPoints: 1100, NumberPrefix: "INV"}
+
+// Init membaca tarif PPN dan identitas penjual dari environment
+func Init() error {
+	rate, err := ParseRate(config.GetEnv("INVOICE_TAX_RATE", "11"))
+	if err != nil {
+		return fmt.Errorf("INVOICE_TAX_RATE tidak valid: %w", err)
+	}
+	settings = Settings{
+		TaxRateBasisPoints: rate,
+		NumberPrefix:       config.GetEnv("INVOICE_NUMBER_PREFIX", "INV"),
+		SellerName:         config.GetEnv("INVOICE_SELLER_NAME", "Backend API"),
+		SellerAddress:      strings.ReplaceAll(config.GetEnv("INVOICE_SELLER_ADDRESS", ""), `\n`, "\n"),
+		SellerTaxID:        config.GetEnv("INVOICE_SELLER_NPWP", ""),
+	}
+	return nil
+}
+
+// ParseRate membaca tarif persen desimal seperti "11" atau "11.5" menjadi basis point tanpa float
+func ParseRate(value string) (int64, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSuffix(strings.TrimSpace(value), "%"), ".")
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("tarif %q maksimal dua desimal", value)
+	}
+	rate, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", 2-len(fraction)), 10, 64)
+	if err != nil || rate < 0 || rate > 10000 {
+		return 0, fmt.Errorf("tarif %q harus antara 0 dan 100", value)
+	}
+	return rate, nil
+}
+
+// SplitTax memisahkan total yang sudah termasuk pajak menjadi dasar pengenaan pajak dan nominal pajak.
+// Pajak dibulatkan half-up ke rupiah terdekat dan dasar pengenaan pajak adalah sisanya, sehingga
+// keduanya selalu berjumlah tepat sama dengan total.
+func SplitTax(total money.Money, rateBasisPoints int64) (subtotal, tax money.Money) {
+	tax = total.MulRat(rateBasisPoints, 10000+rateBasisPoints, money.RoundHalfUp)
+	subtotal, _ = total.Sub(tax)
+	return subtotal, tax
+}
+
+// Issue menerbitkan invoice untuk order yang lunas di dalam transaksi tx. Nomor urut diambil dari
+// InvoiceCounter yang dikunci hingga transaksi selesai, sehingga nomor berurutan tanpa celah.
+// Jika order sudah memiliki invoice, invoice tersebut dikembalikan.
+func Issue(tx *gorm.DB, order *models.Order) (models.Invoice, error) {
+	var invoice models.Invoice
+	err := tx.Where("order_id = ?", order.ID).First(&invoice).Error
+	if err == nil {
+		return invoice, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return invoice, err
+	}
+
+	var user models.User
+	if err := tx.Unscoped().Where("id = ?", order.UserID).First(&user).Error; err != nil {
+		return invoice, err
+	}
+	var pkg models.Package
+	if order.PackageID == nil {
+		return invoice, fmt.Errorf("order %s is not a package order", order.OrderNumber)
+	}
+	if err := tx.Unscoped().Where("id = ?", *order.PackageID).First(&pkg).Error; err != nil {
+		return invoice, err
+	}
+	var line models.Line
+	if order.LineID != nil {
+		if err := tx.Unscoped().Where("id = ?", *order.LineID).First(&line).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return invoice, err
+		}
+	}
+
+	issuedAt := time.Now()
+	if order.PaidAt != nil {
+		issuedAt = *order.PaidAt
+	}
+	year := issuedAt.Year()
+
+	var sequence int64
+	err = tx.Raw(`INSERT INTO invoice_counters (year, last_sequence) VALUES (?, 1)
+		ON CONFLICT (year) DO UPDATE SET last_sequence = invoice_counters.last_sequence + 1
+		RETURNING last_sequence`, year).Scan(&sequence).Error
+	if err != nil {
+		return invoice, err
+	}
+
+	subtotal, tax := SplitTax(order.AmountMoney(), settings.TaxRateBasisPoints)
+	invoice = models.Invoice{
+		Number:             fmt.Sprintf("%s/%d/%06d", settings.NumberPrefix, year, sequence),
+		Year:               year,
+		Sequence:           sequence,
+		OrderID:            order.ID,
+		OrderNumber:        order.OrderNumber,
+		UserID:             order.UserID,
+		IssuedAt:           issuedAt,
+		SellerName:         settings.SellerName,
+		SellerAddress:      settings.SellerAddress,
+		SellerTaxID:        settings.SellerTaxID,
+		BuyerName:          user.Username,
+		BuyerEmail:         user.Email,
+		BuyerPhone:         line.MSISDN,
+		PackageID:          pkg.ID,
+		PackageName:        pkg.Name,
+		PackageData:        pkg.Data,
+		PackageDuration:    pkg.Duration,
+		PackageCategories:  pkg.Categories,
+		Currency:           order.AmountMoney().Currency,
+		Subtotal:           subtotal.Amount,
+		TaxRateBasisPoints: settings.TaxRateBasisPoints,
+		TaxAmount:          tax.Amount,
+		Total:              order.Amount,
+		Discount:           order.Discount,
+		PromoCodes:         order.PromoCodes,
+	}
+	return invoice, tx.Create(&invoice).Error
+}
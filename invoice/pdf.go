@@ -0,0 +1,115 @@
+// invoice/pdf.go
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Ukuran halaman A4 dalam point (1/72 inci)
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// Font standar PDF yang selalu tersedia di setiap viewer sehingga tidak perlu di-embed.
+// Courier dipakai untuk nominal karena lebar hurufnya tetap sehingga dapat dirata kanan.
+const (
+	fontRegular  = "F1"
+	fontBold     = "F2"
+	fontMono     = "F3"
+	fontMonoBold = "F4"
+)
+
+var pdfFonts = []struct {
+	name     string
+	baseFont string
+}{
+	{fontRegular, "Helvetica"},
+	{fontBold, "Helvetica-Bold"},
+	{fontMono, "Courier"},
+	{fontMonoBold, "Courier-Bold"},
+}
+
+// courierWidth adalah lebar setiap huruf Courier per satuan ukuran font
+const courierWidth = 0.6
+
+// pdfDocument adalah penulis PDF satu halaman minimal untuk dokumen teks sederhana seperti invoice.
+// Koordinat memakai sistem PDF: titik (0, 0) berada di kiri bawah halaman.
+type pdfDocument struct {
+	content bytes.Buffer
+}
+
+// text menulis teks dengan sisi kiri di x dan baseline di y
+func (d *pdfDocument) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&d.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDFString(s))
+}
+
+// monoRight menulis teks Courier (fontMono atau fontMonoBold) dengan sisi kanan di right
+func (d *pdfDocument) monoRight(font string, size, right, y float64, s string) {
+	width := float64(len([]rune(s))) * courierWidth * size
+	d.text(font, size, right-width, y, s)
+}
+
+// line menggambar garis dengan tebal width
+func (d *pdfDocument) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&d.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Bytes menyusun objek PDF beserta tabel xref
+func (d *pdfDocument) Bytes() []byte {
+	var fontRefs strings.Builder
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+	}
+	firstFont := 5
+	for i, font := range pdfFonts {
+		fmt.Fprintf(&fontRefs, " /%s %d 0 R", font.name, firstFont+i)
+	}
+	objects = append(objects,
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font <<%s >> >> /Contents 4 0 R >>",
+			pageWidth, pageHeight, fontRefs.String()),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", d.content.Len(), d.content.String()),
+	)
+	for _, font := range pdfFonts {
+		objects = append(objects, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.baseFont))
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// escapePDFString mengubah teks ke WinAnsiEncoding (Latin-1 untuk huruf yang didukung) dan meng-escape
+// karakter khusus string PDF. Huruf di luar Latin-1 diganti dengan "?".
+func escapePDFString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r < 0x20 || (r >= 0x7f && r < 0xa0) || r > 0xff:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+// invoice/render.go
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Tata letak halaman invoice dalam point
+const (
+	marginLeft  = 50.0
+	marginRight = pageWidth - 50.0
+	amountRight = marginRight
+	lineHeight  = 16.0
+)
+
+// RenderPDF membuat PDF invoice di server tanpa layanan eksternal, dengan label dalam bahasa lang
+func RenderPDF(ctx context.Context, invoice *models.Invoice, lang string) (pdf []byte, err error) {
+	_, span := tracing.StartSpan(ctx, "invoice.render", attribute.String("invoice.number", invoice.Number))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	var d pdfDocument
+	y := pageHeight - 70
+
+	// Kepala: judul dan identitas penjual
+	d.text(fontBold, 22, marginLeft, y, i18n.T(lang, i18n.MsgInvoiceTitle))
+	d.text(fontBold, 12, marginLeft+300, y+6, invoice.SellerName)
+	sellerY := y - 8
+	for _, line := range strings.Split(invoice.SellerAddress, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		d.text(fontRegular, 9, marginLeft+300, sellerY, line)
+		sellerY -= 12
+	}
+	if invoice.SellerTaxID != "" {
+		d.text(fontRegular, 9, marginLeft+300, sellerY, fmt.Sprintf("%s: %s", i18n.T(lang, i18n.MsgInvoiceTaxIDLabel), invoice.SellerTaxID))
+	}
+
+	// Detail invoice
+	y -= 50
+	for _, row := range [][2]string{
+		{i18n.T(lang, i18n.MsgInvoiceNumberLabel), invoice.Number},
+		{i18n.T(lang, i18n.MsgInvoiceDateLabel), invoice.IssuedAt.Format("02-01-2006")},
+		{i18n.T(lang, i18n.MsgInvoiceOrderLabel), invoice.OrderNumber},
+	} {
+		d.text(fontRegular, 10, marginLeft, y, row[0])
+		d.text(fontBold, 10, marginLeft+110, y, row[1])
+		y -= lineHeight
+	}
+	d.text(fontBold, 14, marginLeft+300, y+2*lineHeight, i18n.T(lang, i18n.MsgInvoiceStatusPaid))
+
+	// Pembeli
+	y -= lineHeight
+	d.text(fontBold, 10, marginLeft, y, i18n.T(lang, i18n.MsgInvoiceBilledTo))
+	y -= lineHeight
+	for _, value := range []string{invoice.BuyerName, invoice.BuyerEmail, invoice.BuyerPhone} {
+		if value == "" {
+			continue
+		}
+		d.text(fontRegular, 10, marginLeft, y, value)
+		y -= 14
+	}
+
+	// Baris paket
+	y -= lineHeight
+	d.line(marginLeft, y+12, marginRight, y+12, 1)
+	d.text(fontBold, 10, marginLeft, y, i18n.T(lang, i18n.MsgInvoiceDescription))
+	d.monoRight(fontMonoBold, 10, amountRight, y, i18n.T(lang, i18n.MsgInvoiceAmountLabel))
+	d.line(marginLeft, y-6, marginRight, y-6, 0.5)
+
+	y -= 24
+	d.text(fontBold, 10, marginLeft, y, invoice.PackageName)
+	d.monoRight(fontMono, 10, amountRight, y, invoice.ListPriceMoney().Format())
+	var details []string
+	for _, detail := range []string{invoice.PackageData, invoice.PackageDuration, invoice.PackageCategories} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	if len(details) > 0 {
+		y -= 14
+		d.text(fontRegular, 9, marginLeft, y, strings.Join(details, " - "))
+	}
+	if invoice.Discount > 0 {
+		y -= lineHeight
+		d.text(fontRegular, 10, marginLeft, y, i18n.T(lang, i18n.MsgInvoiceDiscountLabel, invoice.PromoCodes))
+		d.monoRight(fontMono, 10, amountRight, y, "-"+invoice.DiscountMoney().Format())
+	}
+
+	// Ringkasan pajak
+	y -= 14
+	d.line(marginLeft, y, marginRight, y, 0.5)
+	y -= 20
+	labelX := marginLeft + 260
+	d.text(fontRegular, 10, labelX, y, i18n.T(lang, i18n.MsgInvoiceSubtotalLabel))
+	d.monoRight(fontMono, 10, amountRight, y, invoice.SubtotalMoney().Format())
+	y -= lineHeight
+	d.text(fontRegular, 10, labelX, y, i18n.T(lang, i18n.MsgInvoiceTaxLabel, invoice.TaxRateDisplay()))
+	d.monoRight(fontMono, 10, amountRight, y, invoice.TaxMoney().Format())
+	y -= 8
+	d.line(labelX, y, marginRight, y, 1)
+	y -= lineHeight
+	d.text(fontBold, 11, labelX, y, i18n.T(lang, i18n.MsgInvoiceTotalLabel))
+	d.monoRight(fontMonoBold, 11, amountRight, y, invoice.TotalMoney().Format())
+
+	d.text(fontRegular, 8, marginLeft, 60, i18n.T(lang, i18n.MsgInvoiceFooter))
+
+	return d.Bytes(), nil
+}
+
+// FileName mengembalikan nama file PDF invoice, misalnya INV-2026-000042.pdf
+func FileName(invoice *models.Invoice) string {
+	return strings.ReplaceAll(invoice.Number, "/", "-") + ".pdf"
+}
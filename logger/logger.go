@@ -0,0 +1,101 @@
+// logger/logger.go
+package logger
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// Init memasang slog sebagai logger default aplikasi.
+//
+// LOG_LEVEL menentukan level minimum (debug, info, warn, error; default info) dan
+// LOG_FORMAT menentukan format keluaran (json atau text; default json).
+// Pemanggilan log.Printf yang tersisa juga diarahkan ke slog sehingga ikut terstruktur dan disensor.
+func Init() {
+	options := &slog.HandlerOptions{
+		Level:       ParseLevel(os.Getenv("LOG_LEVEL")),
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "text") {
+		handler = slog.NewTextHandler(os.Stdout, options)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	}
+
+	slog.SetDefault(slog.New(&contextHandler{Handler: handler}))
+	log.SetFlags(0)
+}
+
+// ParseLevel mengubah nama level menjadi slog.Level, default Info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithRequestID menyimpan request ID ke context agar ikut tercatat pada setiap log
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID mengambil request ID dari context
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// Fatal mencatat pesan error lalu menghentikan proses, pengganti log.Fatalf
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// contextHandler menambahkan request_id dan trace_id dari context, serta menyensor isi pesan
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, RedactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(attr)
+		return true
+	})
+
+	if requestID := RequestID(ctx); requestID != "" {
+		redacted.AddAttrs(slog.String("request_id", requestID))
+	}
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		redacted.AddAttrs(slog.String("trace_id", traceID))
+	}
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,97 @@
+// logger/redact.go
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Atribut dengan nama berikut selalu disensor seluruhnya
+var secretKeys = map[string]bool{
+	"password":          true,
+	"token":             true,
+	"access_token":      true,
+	"refresh_token":     true,
+	"authorization":     true,
+	"cookie":            true,
+	"secret":            true,
+	"code":              true,
+	"verification_code": true,
+	"otp":               true,
+}
+
+// Atribut dengan nama berikut disamarkan sebagian agar masih bisa dipakai untuk debugging
+var (
+	emailKeys = map[string]bool{"email": true, "recipient": true, "to": true}
+	phoneKeys = map[string]bool{"phone": true, "phone_number": true, "msisdn": true}
+)
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)\S+`)
+	phonePattern  = regexp.MustCompile(`(\+?62|\b0)8[0-9]{7,12}\b`)
+	codePattern   = regexp.MustCompile(`(?i)\b(code|kode|otp)(\s*[:=]?\s*)[A-Z0-9]{4,8}\b`)
+)
+
+// redactAttr dipakai sebagai ReplaceAttr pada handler slog
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+
+	switch {
+	case key == "request_id" || key == "trace_id":
+		return attr
+	case secretKeys[key]:
+		return slog.String(attr.Key, redacted)
+	case emailKeys[key]:
+		return slog.String(attr.Key, MaskEmail(attr.Value.String()))
+	case phoneKeys[key]:
+		return slog.String(attr.Key, MaskPhone(attr.Value.String()))
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactString(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, RedactString(err.Error()))
+		}
+	}
+	return attr
+}
+
+// RedactString menyamarkan email, nomor telepon, token dan kode verifikasi di dalam teks bebas
+func RedactString(s string) string {
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = bearerPattern.ReplaceAllString(s, "${1}"+redacted)
+	s = codePattern.ReplaceAllString(s, "${1}${2}"+redacted)
+	s = emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+	s = phonePattern.ReplaceAllStringFunc(s, MaskPhone)
+	return s
+}
+
+// MaskEmail menyisakan huruf pertama dan domain, misalnya "u***@example.com"
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		if email == "" {
+			return ""
+		}
+		return redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// MaskPhone menyisakan tiga digit terakhir, misalnya "*********123"
+func MaskPhone(phone string) string {
+	if len(phone) <= 3 {
+		if phone == "" {
+			return ""
+		}
+		return redacted
+	}
+	return strings.Repeat("*", len(phone)-3) + phone[len(phone)-3:]
+}
@@ -2,15 +2,32 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv" // Untuk memuat file .env
 	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/invoice"
+	"github.com/mfuadfakhruzzaki/backend-api/logger"
+	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/payment"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
 	"github.com/mfuadfakhruzzaki/backend-api/routes"
 	"github.com/mfuadfakhruzzaki/backend-api/seeds"
+	"github.com/mfuadfakhruzzaki/backend-api/services"
+	"github.com/mfuadfakhruzzaki/backend-api/sms"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -22,20 +39,54 @@ import (
 func main() {
 	// Memuat variabel environment dari .env
 	err := godotenv.Load()
+
+	// Memasang logger terstruktur (slog) dengan sensor data pribadi
+	logger.Init()
 	if err != nil {
-		log.Println("Tidak menemukan file .env. Menggunakan variabel environment.")
+		slog.Info("Tidak menemukan file .env. Menggunakan variabel environment.")
 	}
 
 	// Memastikan GOOGLE_APPLICATION_CREDENTIALS diatur
 	googleCreds := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
 	if googleCreds == "" {
-		log.Fatal("GOOGLE_APPLICATION_CREDENTIALS tidak diatur di .env")
+		logger.Fatal("GOOGLE_APPLICATION_CREDENTIALS tidak diatur di .env")
 	}
 
 	// Memastikan GCS_BUCKET_NAME diatur
 	bucketName := os.Getenv("GCS_BUCKET_NAME")
 	if bucketName == "" {
-		log.Fatal("GCS_BUCKET_NAME tidak diatur di .env")
+		logger.Fatal("GCS_BUCKET_NAME tidak diatur di .env")
+	}
+
+	// Memasang tracer OpenTelemetry sebelum koneksi database agar query ikut ditrace
+	shutdownTracing, err := tracing.Init(context.Background(), config.Version)
+	if err != nil {
+		logger.Fatal("Gagal menginisialisasi tracing", "error", err)
+	}
+
+	// Memilih backend rate limiter (memory/redis) dan membaca batas endpoint autentikasi
+	if err := ratelimit.Init(); err != nil {
+		logger.Fatal("Gagal menginisialisasi rate limiter", "error", err)
+	}
+
+	// Memilih provider SMS untuk OTP verifikasi nomor telepon
+	if err := sms.Init(); err != nil {
+		logger.Fatal("Gagal menginisialisasi provider SMS", "error", err)
+	}
+
+	// Memilih payment gateway untuk checkout paket
+	if err := payment.Init(); err != nil {
+		logger.Fatal("Gagal menginisialisasi payment gateway", "error", err)
+	}
+
+	// Membaca tarif PPN dan identitas penjual untuk invoice
+	if err := invoice.Init(); err != nil {
+		logger.Fatal("Gagal menginisialisasi invoice", "error", err)
+	}
+
+	// Memuat daftar password bocor yang dipakai untuk memeriksa password baru
+	if err := utils.LoadBreachedPasswords(config.LoadPasswordPolicy().BreachedPasswordsPath); err != nil {
+		logger.Fatal("Gagal memuat daftar password bocor", "error", err)
 	}
 
 	// Menghubungkan ke database dan menjalankan migrasi di config.ConnectDatabase()
@@ -44,8 +95,39 @@ func main() {
 	// Menjalankan seeding data paket
 	seeds.SeedPackages()
 
-	// Membuat router baru dengan Gin
-	router := gin.Default()
+	// Membersihkan data pribadi akun yang masa tenggang penghapusannya sudah lewat
+	services.StartPeriodicWorker("account-purge", config.GetEnvDuration("ACCOUNT_PURGE_INTERVAL", time.Hour), services.PurgeDeletedAccounts)
+
+	// Menandai order yang tidak dibayar hingga batas waktunya sebagai expired
+	services.StartPeriodicWorker("order-expiry", config.GetEnvDuration("ORDER_EXPIRY_INTERVAL", 5*time.Minute), services.ExpirePendingOrders)
+
+	// Mengirim email tanda terima beserta PDF invoice untuk order yang sudah lunas
+	services.StartPeriodicWorker("invoice-receipts", config.GetEnvDuration("INVOICE_RECEIPT_INTERVAL", time.Minute), services.SendInvoiceReceipts)
+
+	// Mendaftarkan terjemahan pesan validasi (id & en) ke validator gin
+	i18n.RegisterValidatorTranslations()
+
+	// Pesan debug gin ikut dicatat melalui slog
+	gin.DebugPrintFunc = func(format string, values ...interface{}) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+	gin.DebugPrintRouteFunc = func(httpMethod, absolutePath, handlerName string, _ int) {}
+
+	// Membuat router baru dengan Gin. Tracing dipasang paling awal agar request ID,
+	// access log dan recovery berjalan di dalam span request.
+	router := gin.New()
+
+	// Hanya mempercayai X-Forwarded-For dari proxy yang terdaftar agar IP klien
+	// untuk rate limit tidak dapat dipalsukan
+	if err := router.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		logger.Fatal("TRUSTED_PROXIES tidak valid", "error", err)
+	}
+	router.Use(middleware.TracingMiddleware()...)
+	router.Use(
+		middleware.RequestIDMiddleware(),
+		middleware.AccessLogMiddleware(),
+		middleware.RecoveryMiddleware(),
+	)
 
 	// Mengatur batas ukuran multipart form (misalnya 10 MB)
 	router.MaxMultipartMemory = 10 << 20 // 10 MB
@@ -59,16 +141,76 @@ func main() {
 	// Menambahkan rute untuk Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// Menjalankan server pada port 8080
-	fmt.Println("Server berjalan pada port 8080")
-	if err := router.Run(":8080"); err != nil {
-		log.Fatalf("Gagal menjalankan server: %v", err)
+	// Mengaktifkan HTTP/2 cleartext (h2c) jika diminta
+	serverConfig := config.LoadServerConfig()
+	router.UseH2C = serverConfig.H2C && !serverConfig.TLSEnabled()
+
+	srv := &http.Server{
+		Addr:              serverConfig.Addr,
+		Handler:           router.Handler(),
+		ReadTimeout:       serverConfig.ReadTimeout,
+		ReadHeaderTimeout: serverConfig.ReadHeaderTimeout,
+		WriteTimeout:      serverConfig.WriteTimeout,
+		IdleTimeout:       serverConfig.IdleTimeout,
+		MaxHeaderBytes:    serverConfig.MaxHeaderBytes,
+	}
+
+	// Menjalankan server di goroutine terpisah agar main dapat menunggu sinyal shutdown
+	serverErr := make(chan error, 1)
+	go func() {
+		var err error
+		if serverConfig.TLSEnabled() {
+			slog.Info("Server berjalan", "addr", serverConfig.Addr, "tls", true)
+			err = srv.ListenAndServeTLS(serverConfig.TLSCertFile, serverConfig.TLSKeyFile)
+		} else {
+			slog.Info("Server berjalan", "addr", serverConfig.Addr, "tls", false, "h2c", router.UseH2C)
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErr:
+		logger.Fatal("Gagal menjalankan server", "error", err)
+	case sig := <-quit:
+		slog.Info("Menerima sinyal, memulai graceful shutdown", "signal", sig.String())
+	}
+
+	shutdown(srv, serverConfig, shutdownTracing)
+}
+
+// shutdown menandai readiness false, menunggu load balancer mengalihkan traffic,
+// lalu menyelesaikan request yang sedang berjalan, menghentikan worker dan menutup pool database
+func shutdown(srv *http.Server, serverConfig config.ServerConfig, shutdownTracing func(context.Context) error) {
+	services.MarkShuttingDown()
+	time.Sleep(serverConfig.DrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("Gagal menghentikan server dengan rapi", "error", err)
+	}
+	if err := services.StopWorkers(ctx); err != nil {
+		slog.Warn("Worker latar belakang belum selesai", "error", err)
+	}
+	if err := config.CloseDatabase(); err != nil {
+		slog.Error("Gagal menutup koneksi database", "error", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("Gagal mengirim span terakhir", "error", err)
 	}
+	slog.Info("Server berhenti")
 }
 
 // logRoutes mencetak semua route yang terdaftar
 func logRoutes(router *gin.Engine) {
 	for _, route := range router.Routes() {
-		fmt.Printf("Route terdaftar: %s %s\n", route.Method, route.Path)
+		slog.Debug("Route terdaftar", "method", route.Method, "path", route.Path)
 	}
 }
@@ -0,0 +1,210 @@
+// metrics/metrics.go
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "backend_api"
+
+// Metrik HTTP
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Jumlah request HTTP berdasarkan method, template route dan status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latensi request HTTP berdasarkan method, template route dan status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Metrik database
+var (
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Durasi query GORM berdasarkan operasi, tabel dan hasil.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "result"})
+)
+
+// Metrik integrasi eksternal
+var (
+	EmailsSentTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "email",
+		Name:      "sent_total",
+		Help:      "Jumlah pengiriman email berdasarkan jenis dan hasil (success/failure).",
+	}, []string{"type", "result"})
+
+	SMSSentTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sms",
+		Name:      "sent_total",
+		Help:      "Jumlah pengiriman SMS berdasarkan jenis, provider, dan hasil (success/failure).",
+	}, []string{"type", "provider", "result"})
+
+	StorageUploadBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "upload_size_bytes",
+		Help:      "Ukuran file yang diunggah ke object storage.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 8), // 1 KiB s.d. 16 MiB
+	}, []string{"kind", "result"})
+)
+
+// Metrik perlindungan
+var (
+	RateLimitedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "security",
+		Name:      "rate_limited_total",
+		Help:      "Jumlah request yang ditolak rate limiter berdasarkan scope.",
+	}, []string{"scope"})
+
+	LockoutsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "security",
+		Name:      "lockouts_total",
+		Help:      "Jumlah akun yang dikunci sementara karena kegagalan berulang.",
+	}, []string{"scope"})
+)
+
+// Metrik bisnis
+var (
+	RegistrationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "registrations_total",
+		Help:      "Jumlah percobaan registrasi berdasarkan hasil.",
+	}, []string{"result"})
+
+	EmailVerificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "email_verifications_total",
+		Help:      "Jumlah percobaan verifikasi email berdasarkan hasil.",
+	}, []string{"result"})
+
+	LoginsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "logins_total",
+		Help:      "Jumlah percobaan login berdasarkan hasil.",
+	}, []string{"result"})
+
+	TwoFactorEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "two_factor_events_total",
+		Help:      "Jumlah aktivitas autentikasi dua faktor berdasarkan jenis dan hasil.",
+	}, []string{"event", "result"})
+
+	PasswordChangesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "password_changes_total",
+		Help:      "Jumlah percobaan perubahan password berdasarkan hasil.",
+	}, []string{"result"})
+
+	PasswordRehashesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "password_rehashes_total",
+		Help:      "Jumlah hash password yang diperbarui ke algoritma atau parameter terbaru saat login.",
+	})
+
+	EmailChangesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "email_changes_total",
+		Help:      "Jumlah aktivitas penggantian email berdasarkan jenis dan hasil.",
+	}, []string{"event", "result"})
+
+	AccountDeletionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "account_deletions_total",
+		Help:      "Jumlah penghapusan akun (permintaan dan purge data pribadi) berdasarkan hasil.",
+	}, []string{"event", "result"})
+
+	DataExportsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "data_exports_total",
+		Help:      "Jumlah ekspor data pribadi berdasarkan hasil.",
+	}, []string{"result"})
+
+	PhoneVerificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "phone_verifications_total",
+		Help:      "Jumlah aktivitas verifikasi nomor telepon (pengiriman dan konfirmasi OTP) berdasarkan hasil.",
+	}, []string{"event", "result"})
+
+	OrdersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "orders_total",
+		Help:      "Jumlah order paket berdasarkan kejadian (created, paid, failed, expired, gateway_error).",
+	}, []string{"event"})
+
+	PaymentNotificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "payment_notifications_total",
+		Help:      "Jumlah notifikasi pembayaran dari gateway berdasarkan gateway dan hasil pemrosesan.",
+	}, []string{"gateway", "result"})
+
+	InvoicesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "invoices_total",
+		Help:      "Jumlah invoice berdasarkan kejadian (issued, receipt_sent, receipt_failed).",
+	}, []string{"event"})
+
+	WalletTransactionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "wallet_transactions_total",
+		Help:      "Jumlah transaksi wallet berdasarkan jenis jurnal (topup, purchase, refund, adjustment) dan hasil.",
+	}, []string{"type", "result"})
+
+	PromoRedemptionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "promo_redemptions_total",
+		Help:      "Jumlah pemakaian kode promo pada checkout berdasarkan hasil (applied, rejected).",
+	}, []string{"result"})
+
+	OIDCLoginsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "oidc_logins_total",
+		Help:      "Jumlah login melalui provider OpenID Connect berdasarkan provider dan hasil.",
+	}, []string{"provider", "result"})
+
+	PackageSelectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "package_selections_total",
+		Help:      "Jumlah pemilihan paket berdasarkan ID paket.",
+	}, []string{"package_id"})
+)
+
+// Result mengubah error menjadi label hasil "success" atau "failure"
+func Result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
@@ -2,29 +2,39 @@
 package middleware
 
 import (
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
 	"github.com/mfuadfakhruzzaki/backend-api/utils"
 )
 
 type ContextKey string
 
 const (
-	UserContextKey ContextKey = "userEmail"
-	AuthHeader     string     = "Authorization"
-	BearerSchema   string     = "bearer"
+	UserContextKey      ContextKey = "userEmail"
+	SessionIDContextKey ContextKey = "sessionID"
+	AuthHeader          string     = "Authorization"
+	BearerSchema        string     = "bearer"
 )
 
+// sessionTouchInterval membatasi seberapa sering last_seen_at diperbarui agar tidak menulis ke database setiap request
+const sessionTouchInterval = time.Minute
+
 // JWTMiddleware memverifikasi token JWT dan menambahkan email pengguna ke context Gin
 func JWTMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		lang := Language(c)
+
 		// Mengambil header Authorization
 		authHeader := c.GetHeader(AuthHeader)
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, i18n.T(lang, i18n.MsgAuthHeaderMissing))
 			return
 		}
 
@@ -32,24 +42,58 @@ func JWTMiddleware() gin.HandlerFunc {
 		// Format yang diharapkan: "Bearer <token>"
 		tokenParts := strings.SplitN(authHeader, " ", 2)
 		if len(tokenParts) != 2 || strings.ToLower(tokenParts[0]) != BearerSchema {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format. Expected 'Bearer <token>'"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, i18n.T(lang, i18n.MsgAuthHeaderInvalidFormat))
 			return
 		}
 
 		// Mengambil token
 		tokenString := tokenParts[1]
 
-		// Memvalidasi token dan mengambil email
-		email, err := utils.ValidateToken(tokenString)
+		// Memvalidasi token dan mengambil klaim
+		claims, err := utils.ValidateToken(tokenString)
+		if err != nil {
+			slog.InfoContext(c.Request.Context(), "Token JWT tidak valid", "error", err)
+			abortWithError(c, http.StatusUnauthorized, i18n.T(lang, i18n.MsgInvalidToken))
+			return
+		}
+
+		// Pengguna ditentukan melalui sesi (klaim jti), bukan email di token, sehingga sesi yang
+		// dicabut langsung ditolak dan perubahan email tidak membuat token lama tidak valid
+		var session struct {
+			ID         uint
+			UserID     uint
+			IPAddress  string
+			LastSeenAt time.Time
+			Email      string
+			Language   string
+		}
+		err = config.DB.WithContext(c.Request.Context()).Table("sessions").
+			Select("sessions.id, sessions.user_id, sessions.ip_address, sessions.last_seen_at, users.email, users.language").
+			Joins("JOIN users ON users.id = sessions.user_id AND users.deleted_at IS NULL").
+			Where("sessions.token_id = ? AND sessions.revoked_at IS NULL AND sessions.expires_at > ?", claims.Id, time.Now()).
+			Limit(1).Scan(&session).Error
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token: " + err.Error()})
-			c.Abort()
+			abortWithError(c, http.StatusInternalServerError, i18n.T(lang, i18n.MsgDatabaseError))
+			return
+		}
+		if claims.Id == "" || session.ID == 0 {
+			abortWithError(c, http.StatusUnauthorized, i18n.T(lang, i18n.MsgSessionRevoked))
 			return
 		}
 
-		// Menyimpan email ke context
-		c.Set(string(UserContextKey), email)
+		// Menyimpan email, ID pengguna (untuk access log) dan ID sesi ke context
+		c.Set(string(UserContextKey), session.Email)
+		c.Set(string(UserIDContextKey), session.UserID)
+		c.Set(string(SessionIDContextKey), session.ID)
+
+		// Preferensi bahasa yang disimpan lebih diutamakan daripada Accept-Language
+		SetLanguage(c, session.Language)
+
+		// Memperbarui waktu terakhir terlihat dan IP sesi secara berkala
+		if time.Since(session.LastSeenAt) > sessionTouchInterval || session.IPAddress != c.ClientIP() {
+			config.DB.WithContext(c.Request.Context()).Model(&models.Session{}).Where("id = ?", session.ID).
+				UpdateColumns(map[string]interface{}{"last_seen_at": time.Now(), "ip_address": c.ClientIP()})
+		}
 
 		// Melanjutkan ke handler berikutnya
 		c.Next()
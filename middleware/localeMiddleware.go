@@ -0,0 +1,39 @@
+// middleware/localeMiddleware.go
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+)
+
+const LanguageContextKey ContextKey = "language"
+
+// LocaleMiddleware menentukan bahasa respons dari header Accept-Language.
+// Preferensi bahasa yang tersimpan pada akun pengguna akan menimpa nilai ini di JWTMiddleware.
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		c.Set(string(LanguageContextKey), lang)
+		c.Header("Content-Language", lang)
+		c.Next()
+	}
+}
+
+// SetLanguage mengganti bahasa respons untuk request saat ini
+func SetLanguage(c *gin.Context, lang string) {
+	if lang = i18n.Normalize(lang); lang == "" {
+		return
+	}
+	c.Set(string(LanguageContextKey), lang)
+	c.Header("Content-Language", lang)
+}
+
+// Language mengembalikan bahasa respons untuk request saat ini
+func Language(c *gin.Context) string {
+	if lang, ok := c.Get(string(LanguageContextKey)); ok {
+		if langStr, ok := lang.(string); ok && langStr != "" {
+			return langStr
+		}
+	}
+	return i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
@@ -0,0 +1,84 @@
+// middleware/loggingMiddleware.go
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mfuadfakhruzzaki/backend-api/logger"
+)
+
+const (
+	RequestIDHeader                = "X-Request-ID"
+	RequestIDContextKey ContextKey = "requestID"
+	UserIDContextKey    ContextKey = "userID"
+	maxRequestIDLength             = 128
+)
+
+// requestIDPattern membatasi request ID dari klien agar tidak bisa menyisipkan karakter aneh ke log
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]+$`)
+
+// RequestIDMiddleware memakai X-Request-ID dari klien (jika valid) atau membuat yang baru,
+// menyimpannya ke context request dan mengembalikannya di header respons
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if len(requestID) > maxRequestIDLength || !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(string(RequestIDContextKey), requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// AccessLogMiddleware mencatat setiap request sebagai log terstruktur, termasuk user ID dan latensi
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if userID, ok := c.Get(string(UserIDContextKey)); ok {
+			attrs = append(attrs, slog.Any("user_id", userID))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		slog.LogAttrs(c.Request.Context(), level, "http_request", attrs...)
+	}
+}
+
+// RecoveryMiddleware menangkap panic, mencatatnya melalui slog dan mengembalikan 500
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		slog.ErrorContext(c.Request.Context(), "Panic saat memproses request", "panic", recovered, "path", c.Request.URL.Path, "stack", string(debug.Stack()))
+		abortWithError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	})
+}
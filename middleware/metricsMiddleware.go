@@ -0,0 +1,40 @@
+// middleware/metricsMiddleware.go
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+)
+
+// knownMethods adalah method HTTP standar yang dipakai apa adanya sebagai label
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// MetricsMiddleware mencatat jumlah dan latensi request berdasarkan template route (bukan path mentah)
+// agar kardinalitas label tetap terkendali
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		// net/http menerima token apa pun sebagai method, jadi method non-standar digabung agar label tetap terbatas
+		method := c.Request.Method
+		if !knownMethods[method] {
+			method = "OTHER"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
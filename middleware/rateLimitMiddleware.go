@@ -0,0 +1,39 @@
+// middleware/rateLimitMiddleware.go
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
+)
+
+// RateLimitByIP membatasi jumlah request per alamat IP klien untuk scope tertentu
+func RateLimitByIP(scope string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := ratelimit.Allow(c.Request.Context(), scope+":ip:"+c.ClientIP(), limit)
+		if !result.Allowed {
+			metrics.RateLimitedTotal.WithLabelValues(scope + "_ip").Inc()
+			AbortTooManyRequests(c, result.RetryAfter, i18n.MsgTooManyRequests)
+			return
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Next()
+	}
+}
+
+// AbortTooManyRequests menghentikan request dengan status 429 dan header Retry-After
+func AbortTooManyRequests(c *gin.Context, retryAfter time.Duration, messageKey string) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	abortWithError(c, http.StatusTooManyRequests, i18n.T(Language(c), messageKey, seconds))
+}
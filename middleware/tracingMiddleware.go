@@ -0,0 +1,34 @@
+// middleware/tracingMiddleware.go
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+)
+
+const TraceIDHeader = "X-Trace-ID"
+
+// TracingMiddleware membuat span untuk setiap request (melanjutkan trace dari header traceparent
+// jika ada) dan mengembalikan trace ID ke klien melalui header X-Trace-ID
+func TracingMiddleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		otelgin.Middleware(tracing.ServiceName()),
+		func(c *gin.Context) {
+			if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+				c.Header(TraceIDHeader, traceID)
+			}
+			c.Next()
+		},
+	}
+}
+
+// abortWithError menghentikan request dengan pesan error dan trace ID request saat ini
+func abortWithError(c *gin.Context, status int, message string) {
+	body := gin.H{"error": message}
+	if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+		body["trace_id"] = traceID
+	}
+	c.AbortWithStatusJSON(status, body)
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// EmailChange adalah permintaan penggantian email yang menunggu konfirmasi dari alamat baru.
+// Setelah dikonfirmasi, UndoTokenHash memungkinkan pemilik alamat lama membatalkan perubahan.
+type EmailChange struct {
+    ID            uint       `gorm:"primarykey"`
+    CreatedAt     time.Time
+    UpdatedAt     time.Time
+
+    UserID        uint       `gorm:"index;not null"`
+    OldEmail      string     `gorm:"not null"`
+    NewEmail      string     `gorm:"not null"`
+    CodeHash      string     `gorm:"size:64;not null"`
+    Attempts      int        `gorm:"default:0"`
+    ExpiresAt     time.Time  `gorm:"not null"`
+    ConfirmedAt   *time.Time
+
+    UndoTokenHash string     `gorm:"size:64;index"`
+    UndoExpiresAt *time.Time
+    UndoneAt      *time.Time
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+)
+
+// Invoice diterbitkan satu kali untuk setiap order yang lunas. Data penjual, pembeli dan paket disalin
+// saat invoice terbit agar dokumen tidak berubah ketika profil atau paket diperbarui.
+type Invoice struct {
+    ID                 uint       `gorm:"primarykey" json:"id"`
+    CreatedAt          time.Time  `json:"created_at"`
+    UpdatedAt          time.Time  `json:"updated_at"`
+
+    // Number berurutan per tahun, misalnya INV/2026/000042; Year dan Sequence menjaga urutannya unik
+    Number             string     `gorm:"size:40;uniqueIndex;not null" json:"number"`
+    Year               int        `gorm:"not null;uniqueIndex:idx_invoices_sequence" json:"-"`
+    Sequence           int64      `gorm:"not null;uniqueIndex:idx_invoices_sequence" json:"-"`
+    OrderID            uint       `gorm:"uniqueIndex;not null" json:"order_id"`
+    OrderNumber        string     `gorm:"size:40;not null" json:"order_number"`
+    UserID             uint       `gorm:"index;not null" json:"-"`
+    IssuedAt           time.Time  `gorm:"not null" json:"issued_at"`
+
+    SellerName         string     `json:"seller_name"`
+    SellerAddress      string     `json:"seller_address"`
+    SellerTaxID        string     `gorm:"size:30" json:"seller_tax_id"`
+
+    BuyerName          string     `json:"buyer_name"`
+    BuyerEmail         string     `json:"buyer_email"`
+    BuyerPhone         string     `json:"buyer_phone"`
+
+    PackageID          uint       `gorm:"not null" json:"package_id"`
+    PackageName        string     `json:"package_name"`
+    PackageData        string     `json:"package_data"`
+    PackageDuration    string     `json:"package_duration"`
+    PackageCategories  string     `json:"package_categories"`
+
+    // Nominal dalam minor unit Currency. Harga sudah termasuk PPN, sehingga
+    // Subtotal (dasar pengenaan pajak) + TaxAmount = Total = nominal yang dibayar.
+    Currency           string     `gorm:"size:3;not null" json:"currency"`
+    Subtotal           int64      `gorm:"not null" json:"subtotal"`
+    // TaxRateBasisPoints adalah tarif PPN dalam basis point (1100 = 11%)
+    TaxRateBasisPoints int64      `gorm:"not null" json:"tax_rate_basis_points"`
+    TaxAmount          int64      `gorm:"not null" json:"tax_amount"`
+    Total              int64      `gorm:"not null" json:"total"`
+    // Discount adalah potongan dari kode promo (PromoCodes); harga paket sebelum diskon = Total + Discount
+    Discount           int64      `gorm:"not null;default:0" json:"discount"`
+    PromoCodes         string     `gorm:"size:100;not null;default:''" json:"promo_codes,omitempty"`
+
+    // ReceiptSentAt diisi setelah email tanda terima beserta PDF invoice terkirim
+    ReceiptSentAt      *time.Time `json:"receipt_sent_at,omitempty"`
+    ReceiptAttempts    int        `gorm:"default:0" json:"-"`
+}
+
+// SubtotalMoney mengembalikan dasar pengenaan pajak sebagai Money
+func (i Invoice) SubtotalMoney() money.Money {
+    return money.New(i.Subtotal, currencyOrDefault(i.Currency))
+}
+
+// TaxMoney mengembalikan nominal PPN sebagai Money
+func (i Invoice) TaxMoney() money.Money {
+    return money.New(i.TaxAmount, currencyOrDefault(i.Currency))
+}
+
+// TotalMoney mengembalikan total yang dibayar sebagai Money
+func (i Invoice) TotalMoney() money.Money {
+    return money.New(i.Total, currencyOrDefault(i.Currency))
+}
+
+// DiscountMoney mengembalikan potongan promo sebagai Money
+func (i Invoice) DiscountMoney() money.Money {
+    return money.New(i.Discount, currencyOrDefault(i.Currency))
+}
+
+// ListPriceMoney mengembalikan harga paket sebelum diskon sebagai Money
+func (i Invoice) ListPriceMoney() money.Money {
+    return money.New(i.Total+i.Discount, currencyOrDefault(i.Currency))
+}
+
+// TaxRateDisplay mengembalikan tarif PPN untuk ditampilkan, misalnya "11%" atau "11.5%"
+func (i Invoice) TaxRateDisplay() string {
+    rate := fmt.Sprintf("%d", i.TaxRateBasisPoints/100)
+    if fraction := i.TaxRateBasisPoints % 100; fraction != 0 {
+        rate += fmt.Sprintf(".%02d", fraction)
+        for rate[len(rate)-1] == '0' {
+            rate = rate[:len(rate)-1]
+        }
+    }
+    return rate + "%"
+}
+
+// MarshalJSON menambahkan nominal terformat (misalnya "Rp164.000") dan tarif PPN di samping nominal mentah
+func (i Invoice) MarshalJSON() ([]byte, error) {
+    type plain Invoice
+    return json.Marshal(struct {
+        plain
+        TaxRate         string `json:"tax_rate"`
+        SubtotalDisplay string `json:"subtotal_display"`
+        TaxDisplay      string `json:"tax_amount_display"`
+        TotalDisplay    string `json:"total_display"`
+        DiscountDisplay string `json:"discount_display"`
+    }{plain(i), i.TaxRateDisplay(), i.SubtotalMoney().Format(), i.TaxMoney().Format(), i.TotalMoney().Format(), i.DiscountMoney().Format()})
+}
+
+// InvoiceCounter menyimpan nomor urut invoice terakhir per tahun. Barisnya dikunci saat nomor baru diambil
+// di dalam transaksi pembayaran, sehingga nomor tidak loncat atau ganda.
+type InvoiceCounter struct {
+    Year         int   `gorm:"primarykey;autoIncrement:false"`
+    LastSequence int64 `gorm:"not null"`
+}
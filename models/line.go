@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Line adalah nomor seluler (SIM) milik pengguna, misalnya nomor sendiri, tablet, atau anggota keluarga.
+// Langganan paket melekat pada line. Line utama (IsPrimary) dicerminkan ke User.PhoneNumber dan User.PackageID.
+type Line struct {
+    ID         uint           `gorm:"primarykey" json:"id"`
+    CreatedAt  time.Time      `json:"created_at"`
+    UpdatedAt  time.Time      `json:"updated_at"`
+    DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+    // Setiap pengguna hanya memiliki satu line utama
+    UserID     uint           `gorm:"index;not null;uniqueIndex:idx_lines_user_primary,where:is_primary AND deleted_at IS NULL" json:"-"`
+    // MSISDN disimpan dalam format E.164 dan unik di antara line yang belum dihapus
+    MSISDN     string         `gorm:"column:msisdn;size:16;not null;uniqueIndex:idx_lines_msisdn_active,where:deleted_at IS NULL" json:"msisdn"`
+    Label      string         `gorm:"size:50" json:"label"`
+    Operator   string         `gorm:"size:20" json:"operator"`
+    Verified   bool           `gorm:"default:false" json:"verified"`
+    IsPrimary  bool           `gorm:"default:false" json:"is_primary"`
+
+    PackageID  *uint          `json:"package_id"`
+    Package    *Package       `json:"package,omitempty"`
+}
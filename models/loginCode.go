@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginCode adalah kode login sekali pakai (dan token magic link) untuk login tanpa password.
+// Hanya hash yang disimpan sehingga kode tidak dapat dipakai walaupun database bocor.
+type LoginCode struct {
+    ID        uint       `gorm:"primarykey"`
+    CreatedAt time.Time
+
+    UserID    uint       `gorm:"index;not null"`
+    CodeHash  string     `gorm:"size:64;not null"`
+    TokenHash string     `gorm:"size:64;uniqueIndex;not null"`
+    ExpiresAt time.Time  `gorm:"index;not null"`
+    UsedAt    *time.Time
+    Attempts  int        `gorm:"default:0"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+)
+
+// Status order. Hanya order pending yang dapat berubah; paid, failed dan expired bersifat final
+// kecuali notifikasi lunas yang datang setelah order kedaluwarsa dan refund order lunas oleh admin.
+const (
+	OrderPending  = "pending"
+	OrderPaid     = "paid"
+	OrderFailed   = "failed"
+	OrderExpired  = "expired"
+	// OrderRefunded adalah order lunas yang nominalnya dikembalikan ke saldo wallet oleh admin
+	OrderRefunded = "refunded"
+)
+
+// Jenis order: pembelian paket untuk satu line, atau top-up saldo wallet
+const (
+	OrderTypePackage = "package"
+	OrderTypeTopUp   = "topup"
+)
+
+// Nilai Gateway untuk order yang tidak dibayar melalui payment gateway: dibayar dengan saldo wallet,
+// atau seluruh harganya ditanggung kode promo
+const (
+	GatewayWallet = "wallet"
+	GatewayPromo  = "promo"
+)
+
+// Order adalah pembelian paket untuk satu line atau top-up saldo wallet. Harga paket disalin dari Package
+// saat order dibuat, dan langganan atau saldo baru bertambah setelah pembayaran lunas.
+type Order struct {
+    ID               uint       `gorm:"primarykey" json:"id"`
+    CreatedAt        time.Time  `json:"created_at"`
+    UpdatedAt        time.Time  `json:"updated_at"`
+
+    // OrderNumber dikirim ke gateway sebagai ID order
+    OrderNumber      string     `gorm:"size:40;uniqueIndex;not null" json:"order_number"`
+    UserID           uint       `gorm:"index;not null" json:"-"`
+    Type             string     `gorm:"size:20;not null;default:package" json:"type"`
+    // LineID dan PackageID hanya diisi untuk order paket
+    LineID           *uint      `gorm:"index" json:"line_id,omitempty"`
+    PackageID        *uint      `json:"package_id,omitempty"`
+    Package          *Package   `json:"package,omitempty"`
+    // Amount adalah nominal yang dibayar dalam minor unit Currency (rupiah bulat untuk IDR),
+    // yaitu OriginalAmount (harga paket) dikurangi Discount dari kode promo
+    Amount           int64      `gorm:"not null" json:"amount"`
+    OriginalAmount   int64      `gorm:"not null;default:0" json:"original_amount"`
+    Discount         int64      `gorm:"not null;default:0" json:"discount"`
+    // PromoCodes berisi kode promo yang dipakai, dipisah koma dalam urutan alfabet
+    PromoCodes       string     `gorm:"size:100;not null;default:''" json:"promo_codes,omitempty"`
+    Currency         string     `gorm:"size:3;not null" json:"currency"`
+    Status           string     `gorm:"size:20;index;not null" json:"status"`
+
+    Gateway          string     `gorm:"size:20;not null" json:"gateway"`
+    GatewayReference string     `gorm:"size:100" json:"-"`
+    PaymentURL       string     `json:"payment_url,omitempty"`
+    ExpiresAt        time.Time  `gorm:"index;not null" json:"expires_at"`
+    PaidAt           *time.Time `json:"paid_at,omitempty"`
+}
+
+// AmountMoney mengembalikan nominal order sebagai Money
+func (o Order) AmountMoney() money.Money {
+    return money.New(o.Amount, currencyOrDefault(o.Currency))
+}
+
+// DiscountMoney mengembalikan diskon promo order sebagai Money
+func (o Order) DiscountMoney() money.Money {
+    return money.New(o.Discount, currencyOrDefault(o.Currency))
+}
+
+// MarshalJSON menambahkan amount_display dan discount_display (misalnya "Rp164.000") di samping nominal mentah
+func (o Order) MarshalJSON() ([]byte, error) {
+    type plain Order
+    return json.Marshal(struct {
+        plain
+        AmountDisplay   string `json:"amount_display"`
+        DiscountDisplay string `json:"discount_display"`
+    }{plain(o), o.AmountMoney().Format(), o.DiscountMoney().Format()})
+}
+
+// PaymentNotification mencatat setiap notifikasi pembayaran yang sudah diproses. Unique index pada
+// (gateway, event_id) membuat webhook yang dikirim ulang tidak diproses dua kali.
+type PaymentNotification struct {
+    ID          uint      `gorm:"primarykey"`
+    CreatedAt   time.Time
+
+    Gateway     string    `gorm:"size:20;not null;uniqueIndex:idx_payment_notifications_event"`
+    EventID     string    `gorm:"size:150;not null;uniqueIndex:idx_payment_notifications_event"`
+    OrderID     uint      `gorm:"index"`
+    Status      string    `gorm:"size:20;not null"`
+    Payload     string    `gorm:"type:text"`
+}
@@ -1,21 +1,51 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/mfuadfakhruzzaki/backend-api/money"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 type Package struct {
     ID          uint           `gorm:"primarykey" json:"id"`
     CreatedAt   time.Time      `json:"created_at"`
     UpdatedAt   time.Time      `json:"updated_at"`
-    DeletedAt   *time.Time     `json:"deleted_at,omitempty"`
+    DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
 
     Name        string         `json:"name"`
     Data        string         `json:"data"`
     Duration    string         `json:"duration"`
-    Price       float64        `json:"price"`
+    // Price disimpan dalam minor unit mata uang (untuk rupiah: rupiah bulat), bukan float
+    Price       int64          `gorm:"not null" json:"price"`
+    Currency    string         `gorm:"size:3;not null;default:IDR" json:"currency"`
     Details     datatypes.JSON `json:"details" swaggertype:"string"`  // Override to string
     Categories  string         `json:"categories"`
+    // Operator seluler tujuan paket (misalnya telkomsel); kosong berarti berlaku untuk semua operator
+    Operator    string         `gorm:"size:20;index" json:"operator"`
+}
+
+// PriceMoney mengembalikan harga paket sebagai Money
+func (p Package) PriceMoney() money.Money {
+    return money.New(p.Price, currencyOrDefault(p.Currency))
+}
+
+// MarshalJSON menambahkan price_display (misalnya "Rp164.000") di samping nominal mentah
+func (p Package) MarshalJSON() ([]byte, error) {
+    type plain Package
+    return json.Marshal(struct {
+        plain
+        Currency     string `json:"currency"`
+        PriceDisplay string `json:"price_display"`
+    }{plain(p), currencyOrDefault(p.Currency), p.PriceMoney().Format()})
+}
+
+// currencyOrDefault mengisi mata uang kosong (data yang belum dimuat dari database) dengan rupiah
+func currencyOrDefault(currency string) string {
+    if currency == "" {
+        return money.IDR
+    }
+    return currency
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// PhoneVerification adalah kode OTP yang dikirim melalui SMS untuk membuktikan kepemilikan nomor telepon.
+// Kode berlaku untuk satu line; nomor disimpan agar kode tidak berlaku lagi jika nomor line diganti sebelum verifikasi.
+type PhoneVerification struct {
+    ID          uint       `gorm:"primarykey"`
+    CreatedAt   time.Time
+
+    UserID      uint       `gorm:"index;not null"`
+    LineID      uint       `gorm:"index"`
+    PhoneNumber string     `gorm:"not null"`
+    CodeHash    string     `gorm:"size:64;not null"`
+    Attempts    int        `gorm:"default:0"`
+    ExpiresAt   time.Time  `gorm:"not null"`
+    VerifiedAt  *time.Time
+}
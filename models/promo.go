@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Jenis diskon kode promo
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// PromoCode adalah kode promo untuk pembelian paket. Pemakaiannya dihitung dari PromoRedemption milik order
+// yang masih berlaku (pending yang belum kedaluwarsa, paid atau refunded), sehingga order yang gagal atau
+// kedaluwarsa otomatis mengembalikan kuota.
+type PromoCode struct {
+    ID                 uint       `gorm:"primarykey" json:"id"`
+    CreatedAt          time.Time  `json:"created_at"`
+    UpdatedAt          time.Time  `json:"updated_at"`
+
+    // Code disimpan dalam huruf besar dan dicocokkan tanpa membedakan huruf besar/kecil
+    Code               string     `gorm:"size:40;uniqueIndex;not null" json:"code"`
+    Description        string     `json:"description"`
+    Active             bool       `gorm:"not null" json:"active"`
+
+    DiscountType       string     `gorm:"size:10;not null" json:"discount_type"`
+    // PercentBasisPoints untuk diskon persen (1000 = 10%), MaxDiscount membatasi nominalnya (0 = tanpa batas)
+    PercentBasisPoints int64      `json:"percent_basis_points"`
+    MaxDiscount        int64      `json:"max_discount"`
+    // Amount untuk diskon nominal tetap, dalam minor unit Currency
+    Amount             int64      `json:"amount"`
+    Currency           string     `gorm:"size:3;not null;default:IDR" json:"currency"`
+
+    // Masa berlaku; nil berarti tanpa batas
+    StartsAt           *time.Time `json:"starts_at,omitempty"`
+    EndsAt             *time.Time `json:"ends_at,omitempty"`
+
+    // Batas pemakaian total dan per pengguna; 0 berarti tanpa batas
+    MaxUses            int64      `json:"max_uses"`
+    MaxUsesPerUser     int64      `json:"max_uses_per_user"`
+
+    // Kode hanya berlaku untuk paket dengan kategori atau ID di daftar ini; keduanya kosong berarti semua paket
+    Categories         datatypes.JSONSlice[string] `json:"categories" swaggertype:"array,string"`
+    PackageIDs         datatypes.JSONSlice[uint]   `json:"package_ids" swaggertype:"array,integer"`
+
+    // Stackable menandakan kode dapat digabung dengan kode stackable lain dalam satu pembelian
+    Stackable          bool       `gorm:"default:false" json:"stackable"`
+}
+
+// PromoRedemption mencatat pemakaian kode promo pada satu order beserta nominal diskonnya
+type PromoRedemption struct {
+    ID          uint      `gorm:"primarykey" json:"id"`
+    CreatedAt   time.Time `json:"created_at"`
+
+    PromoCodeID uint      `gorm:"not null;uniqueIndex:idx_promo_redemptions_order" json:"promo_code_id"`
+    OrderID     uint      `gorm:"not null;uniqueIndex:idx_promo_redemptions_order;index" json:"order_id"`
+    UserID      uint      `gorm:"index;not null" json:"-"`
+    Discount    int64     `gorm:"not null" json:"discount"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// RecoveryCode adalah kode cadangan sekali pakai untuk login saat aplikasi authenticator tidak tersedia
+type RecoveryCode struct {
+    ID        uint       `gorm:"primarykey" json:"-"`
+    CreatedAt time.Time  `json:"-"`
+    UserID    uint       `gorm:"index;not null" json:"-"`
+    CodeHash  string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+    UsedAt    *time.Time `json:"-"`
+}
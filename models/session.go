@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Session mencatat setiap login beserta perangkatnya. Access token membawa TokenID (klaim jti)
+// sehingga token dapat dicabut dengan mengisi RevokedAt.
+type Session struct {
+    ID         uint       `gorm:"primarykey" json:"id"`
+    CreatedAt  time.Time  `json:"created_at"`
+    UpdatedAt  time.Time  `json:"-"`
+
+    UserID     uint       `gorm:"index;not null" json:"-"`
+    TokenID    string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+    DeviceName string     `gorm:"size:100" json:"device_name"`
+    UserAgent  string     `gorm:"size:512" json:"user_agent"`
+    IPAddress  string     `gorm:"size:45" json:"ip_address"`
+    LastSeenAt time.Time  `json:"last_seen_at"`
+    ExpiresAt  time.Time  `gorm:"index;not null" json:"expires_at"`
+    RevokedAt  *time.Time `json:"-"`
+}
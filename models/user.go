@@ -2,21 +2,45 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Peran pengguna. Admin dapat melakukan refund dan penyesuaian saldo wallet.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
 )
 
 type User struct {
     ID              uint        `gorm:"primarykey" json:"id"`
     CreatedAt       time.Time   `json:"created_at"`
     UpdatedAt       time.Time   `json:"updated_at"`
-    DeletedAt       *time.Time  `json:"deleted_at,omitempty"`
+    DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
 
-    Email           string      `gorm:"uniqueIndex;not null" json:"email"`
-    Username        string      `gorm:"uniqueIndex;not null" json:"username"`
+    // Unique index hanya berlaku untuk akun yang belum dihapus agar email dan username dapat dipakai ulang
+    Email           string      `gorm:"uniqueIndex:idx_users_email_active,where:deleted_at IS NULL;not null" json:"email"`
+    Username        string      `gorm:"uniqueIndex:idx_users_username_active,where:deleted_at IS NULL;not null" json:"username"`
     Password        string      `gorm:"not null" json:"password,omitempty"`
-    PhoneNumber     string      `json:"phone_number"`
+    // PhoneNumber disimpan dalam format E.164 (+628...) dan unik di antara akun yang belum dihapus
+    PhoneNumber     string      `gorm:"uniqueIndex:idx_users_phone_active,where:deleted_at IS NULL AND phone_number <> ''" json:"phone_number"`
+    PhoneOperator   string      `gorm:"size:20" json:"phone_operator"`
+    // PhoneVerified menandakan kepemilikan nomor telepon sudah dibuktikan dengan OTP SMS; direset saat nomor diganti
+    PhoneVerified   bool        `gorm:"default:false" json:"phone_verified"`
     ProfilePicture  string      `json:"profile_picture"`
     PackageID       *uint       `json:"package_id,omitempty"`
     Package         Package     `json:"package,omitempty"`
     EmailVerified   bool        `gorm:"default:false" json:"email_verified"`
     VerificationCode string     `gorm:"size:6" json:"-"`
+    Language        string      `gorm:"size:5" json:"language"`
+    // Role menentukan akses ke endpoint admin (RoleUser atau RoleAdmin)
+    Role            string      `gorm:"size:20;not null;default:user" json:"role"`
+
+    // Autentikasi dua faktor (TOTP). Secret disimpan terenkripsi dan baru aktif setelah dikonfirmasi.
+    TwoFactorEnabled  bool      `gorm:"default:false" json:"two_factor_enabled"`
+    TwoFactorSecret   string    `json:"-"`
+    TwoFactorLastStep int64     `json:"-"`
+
+    // PurgedAt diisi setelah data pribadi akun yang dihapus (DeletedAt) dibersihkan oleh job purge
+    PurgedAt          *time.Time `json:"-"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// UserIdentity menghubungkan akun pengguna dengan identitas dari provider OpenID Connect (misalnya Google)
+type UserIdentity struct {
+    ID        uint      `gorm:"primarykey" json:"id"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+
+    UserID    uint      `gorm:"not null;uniqueIndex:idx_identity_user_provider,priority:1" json:"-"`
+    Provider  string    `gorm:"size:50;not null;uniqueIndex:idx_identity_provider_subject;uniqueIndex:idx_identity_user_provider,priority:2" json:"provider"`
+    Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject" json:"-"`
+    Email     string    `json:"email"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// Jenis akun ledger. Akun wallet milik pengguna tidak boleh bersaldo negatif; akun sistem adalah lawan
+// transaksi (dana dari gateway, pendapatan, penyesuaian) dan boleh bersaldo negatif.
+const (
+	LedgerAccountWallet = "wallet"
+	LedgerAccountSystem = "system"
+)
+
+// Jenis jurnal wallet
+const (
+	JournalTopUp      = "topup"
+	JournalPurchase   = "purchase"
+	JournalRefund     = "refund"
+	JournalAdjustment = "adjustment"
+)
+
+// LedgerAccount adalah akun pada ledger double-entry. Saldonya tidak disimpan, melainkan dihitung dari
+// jumlah LedgerEntry akun tersebut. Baris akun wallet dikunci (SELECT ... FOR UPDATE) selama transaksi
+// yang mengurangi saldo agar pembelian bersamaan tidak membuat saldo negatif.
+type LedgerAccount struct {
+    ID          uint      `gorm:"primarykey" json:"id"`
+    CreatedAt   time.Time `json:"created_at"`
+
+    // Code unik per akun, misalnya wallet:42:IDR atau system:revenue:IDR
+    Code        string    `gorm:"size:60;uniqueIndex;not null" json:"code"`
+    Kind        string    `gorm:"size:20;not null" json:"kind"`
+    UserID      *uint     `gorm:"index" json:"-"`
+    Currency    string    `gorm:"size:3;not null" json:"currency"`
+}
+
+// JournalEntry adalah satu transaksi ledger. Jumlah Amount seluruh LedgerEntry dalam satu jurnal selalu nol.
+// Reference (misalnya nomor order) unik per jenis jurnal agar transaksi yang sama tidak dicatat dua kali.
+type JournalEntry struct {
+    ID          uint          `gorm:"primarykey" json:"id"`
+    CreatedAt   time.Time     `json:"created_at"`
+
+    Type        string        `gorm:"size:20;not null;uniqueIndex:idx_journal_entries_reference,where:reference <> ''" json:"type"`
+    Reference   string        `gorm:"size:60;uniqueIndex:idx_journal_entries_reference,where:reference <> ''" json:"reference,omitempty"`
+    Description string        `json:"description,omitempty"`
+    // CreatedByID diisi dengan ID admin untuk refund dan penyesuaian saldo
+    CreatedByID *uint         `json:"-"`
+    Entries     []LedgerEntry `json:"entries,omitempty"`
+}
+
+// LedgerEntry adalah satu sisi jurnal. Amount bertanda dalam minor unit mata uang akun: positif menambah
+// saldo akun dan negatif menguranginya.
+type LedgerEntry struct {
+    ID             uint      `gorm:"primarykey" json:"id"`
+    CreatedAt      time.Time `json:"created_at"`
+
+    JournalEntryID uint      `gorm:"index;not null" json:"journal_entry_id"`
+    AccountID      uint      `gorm:"index;not null" json:"account_id"`
+    Amount         int64     `gorm:"not null" json:"amount"`
+}
@@ -0,0 +1,257 @@
+// money/money.go
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// IDR adalah kode mata uang rupiah (ISO 4217)
+const IDR = "IDR"
+
+// ErrCurrencyMismatch dikembalikan jika dua nominal dengan mata uang berbeda dijumlahkan atau dibandingkan
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// currency menjelaskan jumlah digit minor unit dan format tampilan sebuah mata uang
+type currency struct {
+	// exponent adalah jumlah digit minor unit. Rupiah tidak memakai sen dalam praktik, sehingga 0.
+	exponent  int
+	symbol    string
+	thousands string
+	decimal   string
+}
+
+var currencies = map[string]currency{
+	IDR:   {exponent: 0, symbol: "Rp", thousands: ".", decimal: ","},
+	"USD": {exponent: 2, symbol: "$", thousands: ",", decimal: "."},
+}
+
+// RoundingMode menentukan pembulatan hasil perkalian dan pembagian ke minor unit
+type RoundingMode int
+
+const (
+	// RoundHalfUp membulatkan 0,5 menjauhi nol (pembulatan umum untuk pajak dan tagihan)
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven membulatkan 0,5 ke angka genap terdekat (banker's rounding)
+	RoundHalfEven
+	// RoundDown membuang sisa pecahan (menuju nol), misalnya agar diskon tidak melebihi batas
+	RoundDown
+	// RoundUp membulatkan setiap sisa pecahan menjauhi nol
+	RoundUp
+)
+
+// Money adalah nominal dalam minor unit (untuk rupiah: rupiah bulat) beserta kode mata uangnya.
+// Nilainya immutable; semua operasi mengembalikan Money baru.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New membuat Money dari nominal dalam minor unit
+func New(amount int64, currencyCode string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currencyCode)}
+}
+
+// Rupiah membuat Money dalam rupiah
+func Rupiah(amount int64) Money {
+	return Money{Amount: amount, Currency: IDR}
+}
+
+// Parse membaca nominal desimal (misalnya "164000.00" dari payment gateway) tanpa melalui float.
+// Digit pecahan melebihi minor unit mata uang harus nol agar tidak ada nilai yang hilang.
+func Parse(value, currencyCode string) (Money, error) {
+	c, err := lookup(currencyCode)
+	if err != nil {
+		return Money{}, err
+	}
+
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+
+	if len(fraction) > c.exponent {
+		if strings.Trim(fraction[c.exponent:], "0") != "" {
+			return Money{}, fmt.Errorf("amount %q has more precision than %s allows", value, currencyCode)
+		}
+		fraction = fraction[:c.exponent]
+	}
+	fraction += strings.Repeat("0", c.exponent-len(fraction))
+
+	amount, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok || !amount.IsInt64() {
+		return Money{}, fmt.Errorf("amount %q out of range", value)
+	}
+	if negative {
+		amount.Neg(amount)
+	}
+	return New(amount.Int64(), currencyCode), nil
+}
+
+// Add menjumlahkan dua nominal dengan mata uang yang sama
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub mengurangkan other dari m dengan mata uang yang sama
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul mengalikan nominal dengan bilangan bulat (misalnya jumlah item)
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+// MulRat mengalikan nominal dengan pecahan num/den lalu membulatkannya ke minor unit sesuai mode,
+// misalnya pajak 11% dengan MulRat(11, 100, RoundHalfUp)
+func (m Money) MulRat(num, den int64, mode RoundingMode) Money {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	return Money{Amount: divRound(product, big.NewInt(den), mode), Currency: m.Currency}
+}
+
+// Percent menghitung basisPoints/10000 dari nominal (100 basis point = 1%)
+func (m Money) Percent(basisPoints int64, mode RoundingMode) Money {
+	return m.MulRat(basisPoints, 10000, mode)
+}
+
+// Equal melaporkan apakah dua nominal memiliki nilai dan mata uang yang sama
+func (m Money) Equal(other Money) bool {
+	return m.Amount == other.Amount && m.Currency == other.Currency
+}
+
+// IsZero melaporkan apakah nominal bernilai nol
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive melaporkan apakah nominal bernilai lebih dari nol
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsNegative melaporkan apakah nominal bernilai negatif
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Decimal mengembalikan nominal sebagai string desimal dalam major unit, misalnya "164000" atau "12.50"
+func (m Money) Decimal() string {
+	c, err := lookup(m.Currency)
+	if err != nil {
+		return fmt.Sprintf("%d", m.Amount)
+	}
+	whole, fraction, negative := m.split(c)
+	s := whole
+	if fraction != "" {
+		s += "." + fraction
+	}
+	if negative {
+		s = "-" + s
+	}
+	return s
+}
+
+// Format mengembalikan nominal untuk ditampilkan, misalnya "Rp164.000" untuk rupiah
+func (m Money) Format() string {
+	c, err := lookup(m.Currency)
+	if err != nil {
+		return fmt.Sprintf("%s %d", m.Currency, m.Amount)
+	}
+	whole, fraction, negative := m.split(c)
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(c.thousands)
+		}
+		grouped.WriteRune(digit)
+	}
+	s := c.symbol + grouped.String()
+	if fraction != "" {
+		s += c.decimal + fraction
+	}
+	if negative {
+		s = "-" + s
+	}
+	return s
+}
+
+func (m Money) String() string {
+	return m.Format()
+}
+
+// split memisahkan nilai absolut menjadi digit major dan minor unit
+func (m Money) split(c currency) (whole, fraction string, negative bool) {
+	digits := new(big.Int).Abs(big.NewInt(m.Amount)).String()
+	if c.exponent > 0 {
+		if len(digits) <= c.exponent {
+			digits = strings.Repeat("0", c.exponent-len(digits)+1) + digits
+		}
+		whole, fraction = digits[:len(digits)-c.exponent], digits[len(digits)-c.exponent:]
+	} else {
+		whole = digits
+	}
+	return whole, fraction, m.Amount < 0
+}
+
+func lookup(code string) (currency, error) {
+	c, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return currency{}, fmt.Errorf("unsupported currency %q", code)
+	}
+	return c, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// divRound membagi n dengan d lalu membulatkan hasilnya sesuai mode
+func divRound(n, d *big.Int, mode RoundingMode) int64 {
+	if d.Sign() < 0 {
+		n, d = new(big.Int).Neg(n), new(big.Int).Neg(d)
+	}
+	quotient, remainder := new(big.Int).QuoRem(n, d, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient.Int64()
+	}
+
+	// Arah pembulatan menjauhi nol mengikuti tanda hasil bagi
+	away := big.NewInt(int64(n.Sign()))
+	twice := new(big.Int).Abs(new(big.Int).Mul(remainder, big.NewInt(2)))
+
+	switch mode {
+	case RoundDown:
+	case RoundUp:
+		quotient.Add(quotient, away)
+	case RoundHalfEven:
+		if cmp := twice.Cmp(d); cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1) {
+			quotient.Add(quotient, away)
+		}
+	default:
+		if twice.Cmp(d) >= 0 {
+			quotient.Add(quotient, away)
+		}
+	}
+	return quotient.Int64()
+}
@@ -0,0 +1,93 @@
+// payment/fake.go
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+)
+
+// FakeSignatureHeader berisi HMAC-SHA256 (hex) dari body notifikasi gateway palsu
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// FakeNotification adalah body webhook gateway palsu
+type FakeNotification struct {
+	EventID     string `json:"event_id"`
+	OrderNumber string `json:"order_number"`
+	Reference   string `json:"reference"`
+	Status      Status `json:"status"`
+	// Amount dalam minor unit Currency; Currency kosong berarti IDR
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// FakeGateway adalah gateway lokal untuk pengembangan dan pengujian. Tidak ada uang yang ditagih;
+// pembayaran diselesaikan dengan mengirim notifikasi bertanda tangan, misalnya dengan cmd/fakepay.
+type FakeGateway struct {
+	secret []byte
+	payURL string
+}
+
+// NewFakeGateway membuat gateway palsu. payURL (opsional) adalah halaman yang ditampilkan sebagai RedirectURL.
+func NewFakeGateway(secret, payURL string) (*FakeGateway, error) {
+	if secret == "" {
+		return nil, errors.New("PAYMENT_FAKE_SECRET harus diatur untuk gateway fake")
+	}
+	return &FakeGateway{secret: []byte(secret), payURL: payURL}, nil
+}
+
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+func (g *FakeGateway) CreatePayment(_ context.Context, req Request) (Session, error) {
+	session := Session{Reference: "fake-" + req.OrderNumber}
+	if g.payURL != "" {
+		if u, err := url.Parse(g.payURL); err == nil {
+			query := u.Query()
+			query.Set("order", req.OrderNumber)
+			u.RawQuery = query.Encode()
+			session.RedirectURL = u.String()
+		}
+	}
+	return session, nil
+}
+
+func (g *FakeGateway) ParseNotification(header http.Header, body []byte) (Notification, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || !hmac.Equal(signature, g.Sign(body)) {
+		return Notification{}, ErrInvalidSignature
+	}
+
+	var n FakeNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return Notification{}, err
+	}
+	if n.EventID == "" || n.OrderNumber == "" {
+		return Notification{}, errors.New("fake notification requires event_id and order_number")
+	}
+	if n.Currency == "" {
+		n.Currency = money.IDR
+	}
+	return Notification{
+		EventID:     n.EventID,
+		OrderNumber: n.OrderNumber,
+		Reference:   n.Reference,
+		Status:      n.Status,
+		Amount:      money.New(n.Amount, n.Currency),
+	}, nil
+}
+
+// Sign menghitung tanda tangan body notifikasi gateway palsu
+func (g *FakeGateway) Sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,186 @@
+// payment/midtrans.go
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+)
+
+const (
+	midtransSnapSandboxURL    = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransSnapProductionURL = "https://app.midtrans.com/snap/v1/transactions"
+)
+
+// MidtransGateway memulai pembayaran melalui Midtrans Snap dan memverifikasi HTTP notification Midtrans
+type MidtransGateway struct {
+	serverKey string
+	snapURL   string
+	finishURL string
+	client    *http.Client
+}
+
+// NewMidtransGateway membuat adapter Midtrans. finishURL (opsional) adalah halaman tujuan setelah pembayaran.
+func NewMidtransGateway(serverKey string, production bool, finishURL string, timeout time.Duration) (*MidtransGateway, error) {
+	if serverKey == "" {
+		return nil, errors.New("MIDTRANS_SERVER_KEY harus diatur untuk gateway midtrans")
+	}
+	snapURL := midtransSnapSandboxURL
+	if production {
+		snapURL = midtransSnapProductionURL
+	}
+	return &MidtransGateway{
+		serverKey: serverKey,
+		snapURL:   snapURL,
+		finishURL: finishURL,
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (g *MidtransGateway) Name() string {
+	return "midtrans"
+}
+
+func (g *MidtransGateway) CreatePayment(ctx context.Context, req Request) (Session, error) {
+	// Midtrans hanya menerima nominal rupiah bulat
+	if req.Amount.Currency != money.IDR {
+		return Session{}, fmt.Errorf("midtrans: unsupported currency %q", req.Amount.Currency)
+	}
+	payload := map[string]interface{}{
+		"transaction_details": map[string]interface{}{
+			"order_id":     req.OrderNumber,
+			"gross_amount": req.Amount.Amount,
+		},
+		"customer_details": map[string]interface{}{
+			"first_name": req.CustomerName,
+			"email":      req.CustomerEmail,
+			"phone":      req.CustomerPhone,
+		},
+		"item_details": []map[string]interface{}{{
+			"id":       req.OrderNumber,
+			"price":    req.Amount.Amount,
+			"quantity": 1,
+			"name":     truncate(req.Description, 50),
+		}},
+	}
+	if !req.ExpiresAt.IsZero() {
+		payload["expiry"] = map[string]interface{}{
+			"unit":     "minute",
+			"duration": int(time.Until(req.ExpiresAt).Minutes()),
+		}
+	}
+	if g.finishURL != "" {
+		payload["callbacks"] = map[string]string{"finish": g.finishURL}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Session{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.snapURL, bytes.NewReader(body))
+	if err != nil {
+		return Session{}, err
+	}
+	httpReq.SetBasicAuth(g.serverKey, "")
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return Session{}, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return Session{}, err
+	}
+	var result struct {
+		Token         string   `json:"token"`
+		RedirectURL   string   `json:"redirect_url"`
+		ErrorMessages []string `json:"error_messages"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return Session{}, fmt.Errorf("midtrans: unexpected response (status %d)", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 || result.RedirectURL == "" {
+		return Session{}, fmt.Errorf("midtrans: %v (status %d)", result.ErrorMessages, resp.StatusCode)
+	}
+	return Session{Reference: result.Token, RedirectURL: result.RedirectURL}, nil
+}
+
+func (g *MidtransGateway) ParseNotification(_ http.Header, body []byte) (Notification, error) {
+	var n struct {
+		OrderID           string `json:"order_id"`
+		StatusCode        string `json:"status_code"`
+		GrossAmount       string `json:"gross_amount"`
+		SignatureKey      string `json:"signature_key"`
+		TransactionID     string `json:"transaction_id"`
+		TransactionStatus string `json:"transaction_status"`
+		FraudStatus       string `json:"fraud_status"`
+	}
+	if err := json.Unmarshal(body, &n); err != nil {
+		return Notification{}, err
+	}
+
+	// signature_key = SHA512(order_id + status_code + gross_amount + server_key)
+	sum := sha512.Sum512([]byte(n.OrderID + n.StatusCode + n.GrossAmount + g.serverKey))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(n.SignatureKey)) != 1 {
+		return Notification{}, ErrInvalidSignature
+	}
+
+	// gross_amount dikirim sebagai string desimal seperti "164000.00" dan dibaca tanpa float
+	amount, err := money.Parse(n.GrossAmount, money.IDR)
+	if err != nil {
+		return Notification{}, fmt.Errorf("midtrans: invalid gross_amount %q", n.GrossAmount)
+	}
+
+	var status Status
+	switch n.TransactionStatus {
+	case "settlement":
+		status = StatusPaid
+	case "capture":
+		// Pembayaran kartu yang ditandai "challenge" belum boleh dianggap lunas
+		switch n.FraudStatus {
+		case "", "accept":
+			status = StatusPaid
+		case "deny":
+			status = StatusFailed
+		default:
+			status = StatusPending
+		}
+	case "pending", "authorize":
+		status = StatusPending
+	case "expire":
+		status = StatusExpired
+	case "deny", "cancel", "failure":
+		status = StatusFailed
+	default:
+		return Notification{}, fmt.Errorf("midtrans: unsupported transaction_status %q", n.TransactionStatus)
+	}
+
+	return Notification{
+		EventID:     n.TransactionID + ":" + n.TransactionStatus,
+		OrderNumber: n.OrderID,
+		Reference:   n.TransactionID,
+		Status:      status,
+		Amount:      amount,
+	}, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
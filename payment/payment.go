@@ -0,0 +1,118 @@
+// payment/payment.go
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+)
+
+// ErrInvalidSignature dikembalikan jika tanda tangan notifikasi pembayaran tidak valid
+var ErrInvalidSignature = errors.New("invalid payment notification signature")
+
+// Status adalah status pembayaran yang dilaporkan gateway, dinormalkan untuk semua gateway
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusPaid    Status = "paid"
+	StatusFailed  Status = "failed"
+	StatusExpired Status = "expired"
+)
+
+// Request berisi data order yang dikirim ke gateway saat memulai pembayaran
+type Request struct {
+	OrderNumber   string
+	Amount        money.Money
+	Description   string
+	CustomerName  string
+	CustomerEmail string
+	CustomerPhone string
+	ExpiresAt     time.Time
+}
+
+// Session adalah pembayaran yang sudah dibuat di gateway. Pengguna menyelesaikan pembayaran di RedirectURL.
+type Session struct {
+	Reference   string
+	RedirectURL string
+}
+
+// Notification adalah notifikasi pembayaran (webhook) yang tanda tangannya sudah diverifikasi
+type Notification struct {
+	// EventID unik untuk setiap perubahan status sehingga notifikasi yang dikirim ulang dapat dikenali
+	EventID     string
+	OrderNumber string
+	Reference   string
+	Status      Status
+	Amount      money.Money
+}
+
+// Gateway adalah adapter untuk satu payment gateway. Implementasinya harus aman dipakai secara bersamaan.
+type Gateway interface {
+	// Name mengembalikan nama gateway untuk URL webhook, label metrik dan data order
+	Name() string
+	// CreatePayment memulai pembayaran untuk order di gateway
+	CreatePayment(ctx context.Context, req Request) (Session, error)
+	// ParseNotification memverifikasi tanda tangan lalu membaca body webhook
+	ParseNotification(header http.Header, body []byte) (Notification, error)
+}
+
+var defaultGateway Gateway
+
+// Init memilih payment gateway dari environment (PAYMENT_GATEWAY=fake|midtrans)
+func Init() error {
+	timeout := config.GetEnvDuration("PAYMENT_HTTP_TIMEOUT", 15*time.Second)
+
+	name := strings.ToLower(strings.TrimSpace(config.GetEnv("PAYMENT_GATEWAY", "")))
+	if name == "" {
+		// Tanpa gateway yang dipilih eksplisit, server hanya boleh jatuh ke gateway palsu saat pengembangan
+		if !config.IsDevelopment() {
+			return errors.New("PAYMENT_GATEWAY harus diatur; gateway fake hanya dipakai otomatis jika APP_ENV=development")
+		}
+		name = "fake"
+	}
+
+	switch name {
+	case "fake":
+		gateway, err := NewFakeGateway(config.GetEnv("PAYMENT_FAKE_SECRET", ""), config.GetEnv("PAYMENT_FAKE_PAY_URL", ""))
+		if err != nil {
+			return err
+		}
+		slog.Warn("Payment gateway palsu aktif, pembayaran tidak benar-benar ditagih", "gateway", name)
+		defaultGateway = gateway
+	case "midtrans":
+		gateway, err := NewMidtransGateway(
+			config.GetEnv("MIDTRANS_SERVER_KEY", ""),
+			config.GetEnvBool("MIDTRANS_PRODUCTION", false),
+			config.GetEnv("PAYMENT_FINISH_URL", ""),
+			timeout,
+		)
+		if err != nil {
+			return err
+		}
+		defaultGateway = gateway
+	default:
+		return fmt.Errorf("PAYMENT_GATEWAY tidak dikenal: %s", name)
+	}
+	return nil
+}
+
+// Default mengembalikan gateway yang dipakai untuk pembayaran baru
+func Default() Gateway {
+	return defaultGateway
+}
+
+// Lookup mengembalikan gateway aktif jika namanya cocok, untuk memproses webhook dari URL /webhooks/payments/:gateway
+func Lookup(name string) (Gateway, bool) {
+	if defaultGateway == nil || defaultGateway.Name() != name {
+		return nil, false
+	}
+	return defaultGateway, true
+}
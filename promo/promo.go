@@ -0,0 +1,274 @@
+// promo/promo.go
+package promo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNotFound dikembalikan jika kode promo tidak ada
+	ErrNotFound = errors.New("promo code not found")
+	// ErrNotActive dikembalikan jika kode promo dinonaktifkan, belum mulai atau sudah berakhir
+	ErrNotActive = errors.New("promo code is not active")
+	// ErrUsageLimit dikembalikan jika kuota pemakaian total kode promo sudah habis
+	ErrUsageLimit = errors.New("promo code usage limit reached")
+	// ErrUserUsageLimit dikembalikan jika pengguna sudah mencapai batas pemakaian kode promo
+	ErrUserUsageLimit = errors.New("promo code usage limit per user reached")
+	// ErrNotEligible dikembalikan jika kode promo tidak berlaku untuk paket
+	ErrNotEligible = errors.New("promo code is not valid for this package")
+	// ErrNotStackable dikembalikan jika kode promo yang tidak stackable digabung dengan kode lain
+	ErrNotStackable = errors.New("promo code cannot be combined with other codes")
+	// ErrTooManyCodes dikembalikan jika jumlah kode melebihi PROMO_MAX_CODES
+	ErrTooManyCodes = errors.New("too many promo codes")
+)
+
+// CodeError menandai kode promo yang menyebabkan error
+type CodeError struct {
+	Code string
+	Err  error
+}
+
+func (e *CodeError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Code, e.Err)
+}
+
+func (e *CodeError) Unwrap() error {
+	return e.Err
+}
+
+// Applied adalah diskon dari satu kode promo
+type Applied struct {
+	PromoCodeID     uint   `json:"-"`
+	Code            string `json:"code"`
+	Discount        int64  `json:"discount"`
+	DiscountDisplay string `json:"discount_display"`
+}
+
+// Quote adalah harga paket setelah kode promo diterapkan
+type Quote struct {
+	PackageID       uint      `json:"package_id"`
+	Currency        string    `json:"currency"`
+	Price           int64     `json:"price"`
+	PriceDisplay    string    `json:"price_display"`
+	Discount        int64     `json:"discount"`
+	DiscountDisplay string    `json:"discount_display"`
+	Total           int64     `json:"total"`
+	TotalDisplay    string    `json:"total_display"`
+	PromoCodes      []Applied `json:"promo_codes"`
+}
+
+// ParseCodes memisahkan daftar kode yang dipisah koma, menyeragamkan huruf besar dan membuang duplikat
+func ParseCodes(value string) []string {
+	var codes []string
+	for _, code := range strings.Split(value, ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// MaxCodes mengembalikan jumlah maksimal kode promo dalam satu pembelian
+func MaxCodes() int {
+	return config.GetEnvInt("PROMO_MAX_CODES", 2)
+}
+
+// Preview menghitung harga paket setelah kode promo tanpa mengunci atau mencatat pemakaian
+func Preview(db *gorm.DB, userID uint, pkg *models.Package, codes []string) (Quote, error) {
+	return quote(db, userID, pkg, codes, false)
+}
+
+// Reserve menghitung harga paket setelah kode promo di dalam transaksi tx. Baris kode promo dikunci hingga
+// transaksi selesai sehingga kuota pemakaian tetap akurat saat pembelian bersamaan. Panggil Record
+// setelah order dibuat di transaksi yang sama.
+func Reserve(tx *gorm.DB, userID uint, pkg *models.Package, codes []string) (Quote, error) {
+	return quote(tx, userID, pkg, codes, true)
+}
+
+// Record mencatat pemakaian kode promo pada order
+func Record(tx *gorm.DB, q Quote, userID, orderID uint) error {
+	for _, applied := range q.PromoCodes {
+		err := tx.Create(&models.PromoRedemption{
+			PromoCodeID: applied.PromoCodeID,
+			OrderID:     orderID,
+			UserID:      userID,
+			Discount:    applied.Discount,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CodesString menggabungkan kode yang diterapkan untuk disimpan di order dan invoice
+func (q Quote) CodesString() string {
+	codes := make([]string, len(q.PromoCodes))
+	for i, applied := range q.PromoCodes {
+		codes[i] = applied.Code
+	}
+	return JoinCodes(codes)
+}
+
+// JoinCodes menggabungkan kode promo dalam urutan alfabet sehingga kumpulan kode yang sama selalu
+// menghasilkan nilai yang sama, berapa pun urutan pengirimannya
+func JoinCodes(codes []string) string {
+	sorted := slices.Clone(codes)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func quote(db *gorm.DB, userID uint, pkg *models.Package, codes []string, lock bool) (Quote, error) {
+	price := pkg.PriceMoney()
+	q := Quote{PackageID: pkg.ID, Currency: price.Currency, Price: price.Amount, PromoCodes: []Applied{}}
+
+	if len(codes) > MaxCodes() {
+		return q, ErrTooManyCodes
+	}
+
+	promos := make([]models.PromoCode, 0, len(codes))
+	if len(codes) > 0 {
+		query := db.Where("code IN ?", codes).Order("id")
+		if lock {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.Find(&promos).Error; err != nil {
+			return q, err
+		}
+	}
+	for _, code := range codes {
+		if !slices.ContainsFunc(promos, func(p models.PromoCode) bool { return p.Code == code }) {
+			return q, &CodeError{Code: code, Err: ErrNotFound}
+		}
+	}
+
+	now := time.Now()
+	for i := range promos {
+		promo := &promos[i]
+		if err := checkPromo(db, promo, userID, pkg, now, len(promos)); err != nil {
+			return q, &CodeError{Code: promo.Code, Err: err}
+		}
+	}
+
+	// Diskon persen diterapkan lebih dulu, lalu diskon nominal tetap, masing-masing pada sisa harga.
+	// Di dalam jenis yang sama urutannya mengikuti urutan kode yang dikirim.
+	sort.SliceStable(promos, func(i, j int) bool {
+		if promos[i].DiscountType != promos[j].DiscountType {
+			return promos[i].DiscountType == models.DiscountPercent
+		}
+		return slices.Index(codes, promos[i].Code) < slices.Index(codes, promos[j].Code)
+	})
+
+	remaining := price
+	for _, promo := range promos {
+		discount := discountFor(&promo, remaining)
+		remaining, _ = remaining.Sub(discount)
+		q.PromoCodes = append(q.PromoCodes, Applied{
+			PromoCodeID:     promo.ID,
+			Code:            promo.Code,
+			Discount:        discount.Amount,
+			DiscountDisplay: discount.Format(),
+		})
+	}
+
+	discount, _ := price.Sub(remaining)
+	q.PriceDisplay = price.Format()
+	q.Discount, q.DiscountDisplay = discount.Amount, discount.Format()
+	q.Total, q.TotalDisplay = remaining.Amount, remaining.Format()
+	return q, nil
+}
+
+// checkPromo memeriksa masa berlaku, kelayakan paket, aturan penggabungan dan kuota pemakaian kode promo
+func checkPromo(db *gorm.DB, promo *models.PromoCode, userID uint, pkg *models.Package, now time.Time, codeCount int) error {
+	if !promo.Active || (promo.StartsAt != nil && now.Before(*promo.StartsAt)) || (promo.EndsAt != nil && !now.Before(*promo.EndsAt)) {
+		return ErrNotActive
+	}
+	if !eligible(promo, pkg) || (promo.DiscountType == models.DiscountFixed && promo.Currency != pkg.PriceMoney().Currency) {
+		return ErrNotEligible
+	}
+	if codeCount > 1 && !promo.Stackable {
+		return ErrNotStackable
+	}
+
+	if promo.MaxUses > 0 {
+		used, err := countRedemptions(db, promo.ID, 0, now)
+		if err != nil {
+			return err
+		}
+		if used >= promo.MaxUses {
+			return ErrUsageLimit
+		}
+	}
+	if promo.MaxUsesPerUser > 0 {
+		used, err := countRedemptions(db, promo.ID, userID, now)
+		if err != nil {
+			return err
+		}
+		if used >= promo.MaxUsesPerUser {
+			return ErrUserUsageLimit
+		}
+	}
+	return nil
+}
+
+// eligible melaporkan apakah kode promo berlaku untuk paket berdasarkan ID atau kategorinya
+func eligible(promo *models.PromoCode, pkg *models.Package) bool {
+	if len(promo.Categories) == 0 && len(promo.PackageIDs) == 0 {
+		return true
+	}
+	if slices.Contains(promo.PackageIDs, pkg.ID) {
+		return true
+	}
+	return slices.ContainsFunc(promo.Categories, func(category string) bool {
+		return strings.EqualFold(strings.TrimSpace(category), strings.TrimSpace(pkg.Categories))
+	})
+}
+
+// countRedemptions menghitung pemakaian kode promo oleh order yang masih berlaku; userID 0 berarti semua pengguna
+func countRedemptions(db *gorm.DB, promoID, userID uint, now time.Time) (int64, error) {
+	query := db.Model(&models.PromoRedemption{}).
+		Joins("JOIN orders ON orders.id = promo_redemptions.order_id").
+		Where("promo_redemptions.promo_code_id = ?", promoID).
+		Where("orders.status IN ? OR (orders.status = ? AND orders.expires_at > ?)",
+			[]string{models.OrderPaid, models.OrderRefunded}, models.OrderPending, now)
+	if userID != 0 {
+		query = query.Where("promo_redemptions.user_id = ?", userID)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// discountFor menghitung diskon kode promo atas sisa harga. Diskon persen dibulatkan ke bawah agar tidak
+// melebihi persentasenya, dan diskon tidak pernah melebihi sisa harga.
+func discountFor(promo *models.PromoCode, remaining money.Money) money.Money {
+	var discount money.Money
+	switch promo.DiscountType {
+	case models.DiscountPercent:
+		discount = remaining.Percent(promo.PercentBasisPoints, money.RoundDown)
+		if promo.MaxDiscount > 0 && discount.Amount > promo.MaxDiscount {
+			discount = money.New(promo.MaxDiscount, remaining.Currency)
+		}
+	default:
+		discount = money.New(promo.Amount, remaining.Currency)
+	}
+	if discount.Amount > remaining.Amount {
+		discount = remaining
+	}
+	if discount.IsNegative() {
+		discount = money.New(0, remaining.Currency)
+	}
+	return discount
+}
@@ -0,0 +1,151 @@
+// ratelimit/memory.go
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval menentukan seberapa sering entri kedaluwarsa dibersihkan dari memori
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	idleTill time.Time
+}
+
+type failureEntry struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryStore menyimpan token bucket di memori proses. Cocok untuk satu instance;
+// untuk banyak instance gunakan RedisStore agar batasnya berlaku bersama.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	failures  map[string]*failureEntry
+	locks     map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore membuat MemoryStore kosong
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		failures: make(map[string]*failureEntry),
+		locks:    make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Allow mengambil satu token dari bucket milik key
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	rate := limit.Rate()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	// Mengisi ulang token sesuai waktu yang berlalu sejak pembaruan terakhir
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*rate)
+	b.updated = now
+	b.idleTill = now.Add(limit.Period)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+	}
+
+	retryAfter := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	return Result{Allowed: false, RetryAfter: retryAfter}, nil
+}
+
+// AddFailure menambah penghitung kegagalan milik key
+func (s *MemoryStore) AddFailure(_ context.Context, key string, ttl time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry, ok := s.failures[key]
+	if !ok || now.After(entry.expiresAt) {
+		entry = &failureEntry{}
+		s.failures[key] = entry
+	}
+	entry.count++
+	entry.expiresAt = now.Add(ttl)
+	return entry.count, nil
+}
+
+// ResetFailures menghapus penghitung kegagalan dan lockout milik key
+func (s *MemoryStore) ResetFailures(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
+
+// Lock mengunci key hingga durasi tertentu
+func (s *MemoryStore) Lock(_ context.Context, key string, duration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = s.now().Add(duration)
+	return nil
+}
+
+// LockedFor mengembalikan sisa durasi lockout milik key
+func (s *MemoryStore) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.locks[key]
+	if !ok {
+		return 0, nil
+	}
+	remaining := until.Sub(s.now())
+	if remaining <= 0 {
+		delete(s.locks, key)
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+// sweep membuang bucket yang sudah penuh kembali, penghitung dan lockout yang kedaluwarsa.
+// Dipanggil dengan mu terkunci.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.After(b.idleTill) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, entry := range s.failures {
+		if now.After(entry.expiresAt) {
+			delete(s.failures, key)
+		}
+	}
+	for key, until := range s.locks {
+		if now.After(until) {
+			delete(s.locks, key)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// ratelimit/policies.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+)
+
+var defaultStore Store = NewMemoryStore()
+
+// Batas untuk endpoint autentikasi, dibaca dari environment oleh Init
+var (
+	LoginPerIP            = Limit{Burst: 20, Period: time.Minute}
+	LoginPerAccount       = Limit{Burst: 5, Period: time.Minute}
+	RegisterPerIP         = Limit{Burst: 5, Period: 10 * time.Minute}
+	VerifyEmailPerIP      = Limit{Burst: 20, Period: time.Minute}
+	VerifyEmailPerAccount = Limit{Burst: 5, Period: 10 * time.Minute}
+
+	LoginCodeRequestPerIP      = Limit{Burst: 10, Period: 10 * time.Minute}
+	LoginCodeRequestPerAccount = Limit{Burst: 3, Period: 15 * time.Minute}
+	LoginCodeVerifyPerAccount  = Limit{Burst: 5, Period: 10 * time.Minute}
+
+	EmailChangeRequestPerAccount = Limit{Burst: 3, Period: 15 * time.Minute}
+	EmailChangeConfirmPerAccount = Limit{Burst: 5, Period: 10 * time.Minute}
+	EmailChangeUndoPerIP         = Limit{Burst: 10, Period: 10 * time.Minute}
+
+	PhoneOTPRequestPerIP      = Limit{Burst: 10, Period: time.Hour}
+	PhoneOTPRequestPerAccount = Limit{Burst: 3, Period: 15 * time.Minute}
+	PhoneOTPRequestPerNumber  = Limit{Burst: 10, Period: 24 * time.Hour}
+	PhoneOTPVerifyPerAccount  = Limit{Burst: 5, Period: 10 * time.Minute}
+
+	LoginLockout       = LockoutPolicy{Threshold: 5, BaseDuration: time.Minute, MaxDuration: time.Hour, FailureWindow: 24 * time.Hour}
+	VerifyEmailLockout = LockoutPolicy{Threshold: 5, BaseDuration: 5 * time.Minute, MaxDuration: 24 * time.Hour, FailureWindow: 24 * time.Hour}
+	PhoneOTPLockout    = LockoutPolicy{Threshold: 5, BaseDuration: time.Minute, MaxDuration: time.Hour, FailureWindow: 24 * time.Hour}
+)
+
+// Init memilih backend penyimpanan (RATE_LIMIT_BACKEND=memory|redis) dan membaca batas dari environment
+func Init() error {
+	switch backend := strings.ToLower(config.GetEnv("RATE_LIMIT_BACKEND", "memory")); backend {
+	case "memory":
+		defaultStore = NewMemoryStore()
+	case "redis":
+		url := config.GetEnv("RATE_LIMIT_REDIS_URL", config.GetEnv("REDIS_URL", ""))
+		if url == "" {
+			return fmt.Errorf("RATE_LIMIT_REDIS_URL atau REDIS_URL harus diatur untuk backend redis")
+		}
+		store, err := NewRedisStore(url)
+		if err != nil {
+			return fmt.Errorf("gagal membuat koneksi redis: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := store.Ping(ctx); err != nil {
+			return fmt.Errorf("redis tidak dapat dijangkau: %v", err)
+		}
+		defaultStore = store
+	default:
+		return fmt.Errorf("RATE_LIMIT_BACKEND tidak dikenal: %s", backend)
+	}
+
+	LoginPerIP = LimitFromEnv("RATE_LIMIT_LOGIN_IP", "20/1m")
+	LoginPerAccount = LimitFromEnv("RATE_LIMIT_LOGIN_ACCOUNT", "5/1m")
+	RegisterPerIP = LimitFromEnv("RATE_LIMIT_REGISTER_IP", "5/10m")
+	VerifyEmailPerIP = LimitFromEnv("RATE_LIMIT_VERIFY_EMAIL_IP", "20/1m")
+	VerifyEmailPerAccount = LimitFromEnv("RATE_LIMIT_VERIFY_EMAIL_ACCOUNT", "5/10m")
+	LoginCodeRequestPerIP = LimitFromEnv("RATE_LIMIT_LOGIN_CODE_REQUEST_IP", "10/10m")
+	LoginCodeRequestPerAccount = LimitFromEnv("RATE_LIMIT_LOGIN_CODE_REQUEST_ACCOUNT", "3/15m")
+	LoginCodeVerifyPerAccount = LimitFromEnv("RATE_LIMIT_LOGIN_CODE_VERIFY_ACCOUNT", "5/10m")
+	EmailChangeRequestPerAccount = LimitFromEnv("RATE_LIMIT_EMAIL_CHANGE_REQUEST_ACCOUNT", "3/15m")
+	EmailChangeConfirmPerAccount = LimitFromEnv("RATE_LIMIT_EMAIL_CHANGE_CONFIRM_ACCOUNT", "5/10m")
+	EmailChangeUndoPerIP = LimitFromEnv("RATE_LIMIT_EMAIL_CHANGE_UNDO_IP", "10/10m")
+	PhoneOTPRequestPerIP = LimitFromEnv("RATE_LIMIT_PHONE_OTP_REQUEST_IP", "10/1h")
+	PhoneOTPRequestPerAccount = LimitFromEnv("RATE_LIMIT_PHONE_OTP_REQUEST_ACCOUNT", "3/15m")
+	PhoneOTPRequestPerNumber = LimitFromEnv("RATE_LIMIT_PHONE_OTP_REQUEST_NUMBER", "10/24h")
+	PhoneOTPVerifyPerAccount = LimitFromEnv("RATE_LIMIT_PHONE_OTP_VERIFY_ACCOUNT", "5/10m")
+
+	LoginLockout = LockoutPolicyFromEnv("LOGIN_LOCKOUT")
+	VerifyEmailLockout = LockoutPolicyFromEnv("VERIFY_EMAIL_LOCKOUT")
+	PhoneOTPLockout = LockoutPolicyFromEnv("PHONE_OTP_LOCKOUT")
+	return nil
+}
+
+// Allow memeriksa rate limit pada store bawaan. Jika store bermasalah (misalnya Redis mati),
+// request tetap diizinkan agar layanan tidak ikut terhenti.
+func Allow(ctx context.Context, key string, limit Limit) Result {
+	result, err := defaultStore.Allow(ctx, key, limit)
+	if err != nil {
+		slog.WarnContext(ctx, "Rate limiter tidak tersedia, request diizinkan", "error", err)
+		return Result{Allowed: true}
+	}
+	return result
+}
+
+// LockedFor mengembalikan sisa durasi lockout untuk scope dan akun tertentu
+func LockedFor(ctx context.Context, scope, account string) time.Duration {
+	remaining, err := defaultStore.LockedFor(ctx, scope+":"+account)
+	if err != nil {
+		slog.WarnContext(ctx, "Gagal memeriksa lockout", "scope", scope, "error", err)
+		return 0
+	}
+	return remaining
+}
+
+// RecordFailure mencatat kegagalan untuk scope dan akun tertentu, lalu mengunci akun
+// sesuai kebijakan lockout progresif. Mengembalikan durasi lockout (0 jika belum terkunci).
+func RecordFailure(ctx context.Context, scope, account string, policy LockoutPolicy) time.Duration {
+	key := scope + ":" + account
+	failures, err := defaultStore.AddFailure(ctx, key, policy.FailureWindow)
+	if err != nil {
+		slog.WarnContext(ctx, "Gagal mencatat kegagalan", "scope", scope, "error", err)
+		return 0
+	}
+
+	duration := policy.DurationFor(failures)
+	if duration > 0 {
+		if err := defaultStore.Lock(ctx, key, duration); err != nil {
+			slog.WarnContext(ctx, "Gagal mengunci akun", "scope", scope, "error", err)
+			return 0
+		}
+		slog.WarnContext(ctx, "Akun dikunci sementara karena kegagalan berulang",
+			"scope", scope, "failures", failures, "duration", duration.String())
+	}
+	return duration
+}
+
+// ResetFailures menghapus penghitung kegagalan setelah percobaan berhasil
+func ResetFailures(ctx context.Context, scope, account string) {
+	if err := defaultStore.ResetFailures(ctx, scope+":"+account); err != nil {
+		slog.WarnContext(ctx, "Gagal mereset penghitung kegagalan", "scope", scope, "error", err)
+	}
+}
@@ -0,0 +1,116 @@
+// ratelimit/ratelimit.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+)
+
+// Limit mendefinisikan token bucket: Burst token tersedia dan terisi ulang sebanyak Burst per Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Rate mengembalikan jumlah token yang terisi per detik
+func (l Limit) Rate() float64 {
+	if l.Period <= 0 {
+		return 0
+	}
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Result adalah hasil pemeriksaan rate limit
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store menyimpan token bucket dan penghitung kegagalan. Implementasinya harus aman dipakai
+// secara bersamaan dan, untuk deployment dengan banyak pod, dibagi antar instance (lihat RedisStore).
+type Store interface {
+	// Allow mengambil satu token dari bucket milik key
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+	// AddFailure menambah penghitung kegagalan milik key dan mengembalikan total kegagalan berturut-turut
+	AddFailure(ctx context.Context, key string, ttl time.Duration) (int, error)
+	// ResetFailures menghapus penghitung kegagalan dan lockout milik key
+	ResetFailures(ctx context.Context, key string) error
+	// Lock mengunci key hingga durasi tertentu
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	// LockedFor mengembalikan sisa durasi lockout milik key (0 jika tidak terkunci)
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+}
+
+// ParseLimit membaca format "N/durasi", misalnya "10/1m" atau "5/10m"
+func ParseLimit(value string) (Limit, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("format rate limit tidak valid: %q", value)
+	}
+	burst, err := strconv.Atoi(parts[0])
+	if err != nil || burst <= 0 {
+		return Limit{}, fmt.Errorf("jumlah rate limit tidak valid: %q", value)
+	}
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("periode rate limit tidak valid: %q", value)
+	}
+	return Limit{Burst: burst, Period: period}, nil
+}
+
+// LimitFromEnv membaca Limit dari environment variable, atau fallback jika kosong/tidak valid
+func LimitFromEnv(key, fallback string) Limit {
+	value := config.GetEnv(key, fallback)
+	limit, err := ParseLimit(value)
+	if err != nil {
+		slog.Warn("Rate limit tidak valid, menggunakan default", "key", key, "value", value, "error", err)
+		limit, _ = ParseLimit(fallback)
+	}
+	return limit
+}
+
+// LockoutPolicy mengatur lockout progresif setelah kegagalan berulang
+type LockoutPolicy struct {
+	// Threshold adalah jumlah kegagalan berturut-turut sebelum lockout pertama
+	Threshold int
+	// BaseDuration adalah durasi lockout pertama, berlipat dua untuk setiap kegagalan berikutnya
+	BaseDuration time.Duration
+	// MaxDuration membatasi durasi lockout terpanjang
+	MaxDuration time.Duration
+	// FailureWindow adalah masa simpan penghitung kegagalan sejak kegagalan terakhir
+	FailureWindow time.Duration
+}
+
+// LockoutPolicyFromEnv membaca kebijakan lockout dengan prefix tertentu, misalnya LOGIN_LOCKOUT_*
+func LockoutPolicyFromEnv(prefix string) LockoutPolicy {
+	return LockoutPolicy{
+		Threshold:     config.GetEnvInt(prefix+"_THRESHOLD", 5),
+		BaseDuration:  config.GetEnvDuration(prefix+"_BASE", time.Minute),
+		MaxDuration:   config.GetEnvDuration(prefix+"_MAX", time.Hour),
+		FailureWindow: config.GetEnvDuration(prefix+"_WINDOW", 24*time.Hour),
+	}
+}
+
+// DurationFor menghitung durasi lockout untuk jumlah kegagalan tertentu (0 jika belum mencapai threshold)
+func (p LockoutPolicy) DurationFor(failures int) time.Duration {
+	if p.Threshold <= 0 || failures < p.Threshold {
+		return 0
+	}
+	exponent := failures - p.Threshold
+	if exponent > 30 {
+		exponent = 30
+	}
+	duration := time.Duration(float64(p.BaseDuration) * math.Pow(2, float64(exponent)))
+	if p.MaxDuration > 0 && duration > p.MaxDuration {
+		duration = p.MaxDuration
+	}
+	return duration
+}
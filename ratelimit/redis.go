@@ -0,0 +1,124 @@
+// ratelimit/redis.go
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// tokenBucketScript mengisi ulang dan mengambil token secara atomik di sisi Redis
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(data[1])
+local updated = tonumber(data[2])
+if tokens == nil or updated == nil then
+	tokens = burst
+	updated = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, math.floor(tokens), retry}
+`)
+
+// RedisStore menyimpan token bucket di Redis (atau server yang kompatibel seperti Valkey/KeyDB)
+// sehingga batas berlaku bersama untuk semua instance
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore membuat RedisStore dari URL, misalnya redis://:password@localhost:6379/0
+func NewRedisStore(url string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: redis.NewClient(options)}, nil
+}
+
+// Allow mengambil satu token dari bucket milik key
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	ratePerMillisecond := limit.Rate() / 1000
+	now := time.Now().UnixMilli()
+
+	values, err := tokenBucketScript.Run(ctx, s.client, []string{redisKeyPrefix + "bucket:" + key},
+		ratePerMillisecond, limit.Burst, now, limit.Period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 3 {
+		return Result{}, errors.New("respons token bucket dari redis tidak valid")
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// AddFailure menambah penghitung kegagalan milik key
+func (s *RedisStore) AddFailure(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	failureKey := redisKeyPrefix + "failures:" + key
+
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, failureKey)
+	pipe.PExpire(ctx, failureKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+// ResetFailures menghapus penghitung kegagalan dan lockout milik key
+func (s *RedisStore) ResetFailures(ctx context.Context, key string) error {
+	return s.client.Del(ctx, redisKeyPrefix+"failures:"+key, redisKeyPrefix+"lock:"+key).Err()
+}
+
+// Lock mengunci key hingga durasi tertentu
+func (s *RedisStore) Lock(ctx context.Context, key string, duration time.Duration) error {
+	return s.client.Set(ctx, redisKeyPrefix+"lock:"+key, 1, duration).Err()
+}
+
+// LockedFor mengembalikan sisa durasi lockout milik key
+func (s *RedisStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, redisKeyPrefix+"lock:"+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Ping memastikan server Redis dapat dijangkau
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close menutup koneksi ke Redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
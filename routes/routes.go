@@ -1,34 +1,60 @@
 package routes
 
 import (
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/mfuadfakhruzzaki/backend-api/config"
 	"github.com/mfuadfakhruzzaki/backend-api/controllers"
 	"github.com/mfuadfakhruzzaki/backend-api/middleware"
+	"github.com/mfuadfakhruzzaki/backend-api/ratelimit"
 )
 
 func RegisterRoutes(router *gin.Engine) {
-	// Set up CORS middleware
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, 
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
+	// Mencatat jumlah dan latensi request untuk Prometheus
+	router.Use(middleware.MetricsMiddleware())
+
+	// Header keamanan, CORS dan batas ukuran body sesuai konfigurasi environment
+	securityConfig := config.LoadSecurityConfig()
+	router.Use(middleware.SecurityHeadersMiddleware(securityConfig))
+	router.Use(middleware.CORSMiddleware(config.LoadCORSConfig()))
+	router.Use(middleware.BodySizeLimitMiddleware(securityConfig.MaxBodyBytes))
+
+	// Menentukan bahasa respons dari header Accept-Language
+	router.Use(middleware.LocaleMiddleware())
+
+	// Health, readiness dan informasi build untuk orchestrator
+	router.GET("/healthz", controllers.Healthz)
+	router.GET("/readyz", controllers.Readyz)
+	router.GET("/version", controllers.Version)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Public Routes
 	public := router.Group("/")
 	{
-		// Registration and Login Endpoints
-		public.POST("/auth/register", controllers.Register)      
-		public.POST("/auth/login", controllers.Login)
+		// Registration and Login Endpoints (dibatasi per IP untuk mencegah brute force)
+		public.POST("/auth/register", middleware.RateLimitByIP("register", ratelimit.RegisterPerIP), controllers.Register)
+		public.POST("/auth/login", middleware.RateLimitByIP("login", ratelimit.LoginPerIP), controllers.Login)
+		public.POST("/auth/login/2fa", middleware.RateLimitByIP("login_2fa", ratelimit.LoginPerIP), controllers.LoginTwoFactor)
+
+		// Login dengan provider OpenID Connect (misalnya Google)
+		public.GET("/auth/oidc/:provider/login", middleware.RateLimitByIP("oidc", ratelimit.LoginPerIP), controllers.OIDCLogin)
+		public.GET("/auth/oidc/:provider/callback", middleware.RateLimitByIP("oidc", ratelimit.LoginPerIP), controllers.OIDCCallback)
+		public.POST("/auth/oidc/link", middleware.RateLimitByIP("login", ratelimit.LoginPerIP), controllers.LinkOIDCIdentity)
+
+		// Login tanpa password dengan kode atau magic link melalui email (PASSWORDLESS_ENABLED)
+		public.POST("/auth/passwordless/request", middleware.RateLimitByIP("login_code_request", ratelimit.LoginCodeRequestPerIP), controllers.RequestLoginCode)
+		public.POST("/auth/passwordless/verify", middleware.RateLimitByIP("login_code", ratelimit.LoginPerIP), controllers.VerifyLoginCode)
+
+		// Membatalkan penggantian email dari tautan yang dikirim ke alamat lama
+		public.POST("/auth/email/undo", middleware.RateLimitByIP("email_change_undo", ratelimit.EmailChangeUndoPerIP), controllers.UndoEmailChange)
+
+		// Notifikasi pembayaran dari payment gateway (diverifikasi dengan tanda tangan, bukan JWT)
+		public.POST("/webhooks/payments/:gateway", controllers.PaymentWebhook)
 
 		// Endpoint untuk verifikasi email
-		public.POST("/auth/verify-email", controllers.VerifyEmail)
+		public.POST("/auth/verify-email", middleware.RateLimitByIP("verify_email", ratelimit.VerifyEmailPerIP), controllers.VerifyEmail)
 
-		
 	}
 
 	// Protected Routes with JWT Middleware
@@ -38,7 +64,21 @@ func RegisterRoutes(router *gin.Engine) {
 		// Package Endpoints
 		api.GET("/packages", controllers.GetPackages)               // Mendapatkan semua paket
 		api.GET("/packages/:id", controllers.GetPackageByID)        // Mendapatkan satu paket berdasarkan ID
-		api.POST("/packages/:id/select", controllers.SelectPackage) // Memilih paket berdasarkan ID
+		api.POST("/packages/:id/select", controllers.SelectPackage) // Membuat order dan memulai pembayaran paket
+		api.GET("/packages/:id/quote", controllers.GetPackageQuote) // Menghitung harga paket setelah kode promo
+
+		// Order pembelian paket
+		api.GET("/orders", controllers.GetOrders)
+		api.GET("/orders/:id", controllers.GetOrder)
+
+		// Invoice untuk order yang sudah lunas
+		api.GET("/invoices", controllers.GetInvoices)
+		api.GET("/invoices/:id", controllers.GetInvoice) // Mengunduh PDF invoice
+
+		// Wallet: saldo prabayar dari ledger double-entry
+		api.GET("/wallet", controllers.GetWallet)
+		api.GET("/wallet/transactions", controllers.GetWalletTransactions)
+		api.POST("/wallet/topups", controllers.TopUpWallet)
 
 		// User Endpoints
 		api.POST("/users/profile/picture", controllers.UploadProfilePicture)
@@ -48,7 +88,54 @@ func RegisterRoutes(router *gin.Engine) {
 		api.PUT("/users/profile", controllers.UpdateProfile) // Mengupdate profil secara keseluruhan
 
 		// **Rute Opsional untuk Mengupdate Username dan Nomor Telepon Secara Khusus**
-		api.PUT("/users/profile/username", controllers.UpdateUsername)      // Mengupdate username
+		api.PUT("/users/profile/username", controllers.UpdateUsername)        // Mengupdate username
 		api.PUT("/users/profile/phone_number", controllers.UpdatePhoneNumber) // Mengupdate nomor telepon
+
+		// Mengganti password (sesi lain diakhiri)
+		api.PUT("/users/password", controllers.ChangePassword)
+
+		// Mengganti email dengan kode konfirmasi ke alamat baru
+		api.POST("/users/email", controllers.RequestEmailChange)
+		api.POST("/users/email/confirm", controllers.ConfirmEmailChange)
+
+		// Verifikasi nomor telepon dengan OTP SMS (wajib sebelum memilih paket)
+		api.POST("/users/phone/verify/request", middleware.RateLimitByIP("phone_otp_request", ratelimit.PhoneOTPRequestPerIP), controllers.RequestPhoneVerification)
+		api.POST("/users/phone/verify", controllers.ConfirmPhoneVerification)
+
+		// Nomor seluler (line) milik pengguna; paket dipasang per line
+		api.GET("/users/lines", controllers.GetLines)
+		api.POST("/users/lines", controllers.AddLine)
+		api.PUT("/users/lines/:id", controllers.UpdateLine)
+		api.DELETE("/users/lines/:id", controllers.DeleteLine)
+		api.POST("/users/lines/:id/primary", controllers.SetPrimaryLine)
+		api.POST("/users/lines/:id/verify/request", middleware.RateLimitByIP("phone_otp_request", ratelimit.PhoneOTPRequestPerIP), controllers.RequestLineVerification)
+		api.POST("/users/lines/:id/verify", controllers.ConfirmLineVerification)
+
+		// Ekspor data pribadi dan penghapusan akun (UU PDP)
+		api.GET("/users/export", controllers.ExportAccountData)
+		api.DELETE("/users/account", controllers.DeleteAccount)
+
+		// Akun provider OpenID Connect yang tertaut
+		api.GET("/users/identities", controllers.GetIdentities)
+		api.DELETE("/users/identities/:provider", controllers.UnlinkIdentity)
+
+		// Autentikasi dua faktor (TOTP)
+		api.POST("/users/2fa/setup", controllers.SetupTwoFactor)
+		api.POST("/users/2fa/confirm", controllers.ConfirmTwoFactor)
+		api.POST("/users/2fa/disable", controllers.DisableTwoFactor)
+
+		// Sesi login dan perangkat
+		api.GET("/users/sessions", controllers.GetSessions)
+		api.DELETE("/users/sessions", controllers.RevokeOtherSessions)
+		api.DELETE("/users/sessions/:id", controllers.RevokeSession)
+
+		// Endpoint admin (peran diperiksa di controller)
+		api.GET("/admin/users/:id/wallet", controllers.AdminGetUserWallet)
+		api.GET("/admin/users/:id/wallet/transactions", controllers.AdminGetUserWalletTransactions)
+		api.POST("/admin/users/:id/wallet/adjustments", controllers.AdminAdjustWallet)
+		api.POST("/admin/orders/:id/refund", controllers.AdminRefundOrder)
+		api.GET("/admin/promos", controllers.AdminGetPromos)
+		api.POST("/admin/promos", controllers.AdminCreatePromo)
+		api.PUT("/admin/promos/:id", controllers.AdminUpdatePromo)
 	}
 }
@@ -2,7 +2,7 @@
 package seeds
 
 import (
-	"fmt"
+	"log/slog"
 
 	"github.com/mfuadfakhruzzaki/backend-api/config"
 	"github.com/mfuadfakhruzzaki/backend-api/models"
@@ -13,7 +13,7 @@ func SeedPackages() {
     var count int64
     config.DB.Model(&models.Package{}).Count(&count)
     if count > 0 {
-        fmt.Println("Paket sudah ada, skip seeding.")
+        slog.Info("Paket sudah ada, skip seeding.")
         return
     }
 
@@ -240,5 +240,5 @@ func SeedPackages() {
         config.DB.Create(&p)
     }
 
-    fmt.Println("Seeding data paket selesai.")
+    slog.Info("Seeding data paket selesai.")
 }
@@ -0,0 +1,105 @@
+// services/accountPurge.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// purgeBatchSize membatasi jumlah akun yang dibersihkan per putaran job
+const purgeBatchSize = 100
+
+// AccountDeletionGracePeriod mengembalikan masa tenggang sebelum data akun yang dihapus dibersihkan
+func AccountDeletionGracePeriod() time.Duration {
+	return config.GetEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour)
+}
+
+// PurgeDeletedAccounts membersihkan data pribadi akun yang dihapus lebih lama dari masa tenggang.
+// Baris pengguna tetap ada (dianonimkan) agar data lain yang merujuk ke ID pengguna tetap konsisten.
+func PurgeDeletedAccounts(ctx context.Context) {
+	var users []models.User
+	err := config.DB.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND purged_at IS NULL", time.Now().Add(-AccountDeletionGracePeriod())).
+		Limit(purgeBatchSize).Find(&users).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Gagal mencari akun yang akan dibersihkan", "error", err)
+		return
+	}
+
+	for i := range users {
+		if ctx.Err() != nil {
+			return
+		}
+		err := purgeAccount(ctx, &users[i])
+		metrics.AccountDeletionsTotal.WithLabelValues("purge", metrics.Result(err)).Inc()
+		if err != nil {
+			slog.ErrorContext(ctx, "Gagal membersihkan data akun", "user_id", users[i].ID, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "Data pribadi akun dibersihkan", "user_id", users[i].ID)
+	}
+}
+
+// purgeAccount menghapus gambar profil, riwayat login dan data terkait, lalu menganonimkan baris pengguna.
+// Invoice tetap disimpan untuk keperluan pajak, tetapi data pembeli yang disalin ke dalamnya ikut dianonimkan.
+func purgeAccount(ctx context.Context, user *models.User) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "account.purge", attribute.Int64("user.id", int64(user.ID)))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	if bucketName, objectName, ok := ParseStorageURL(user.ProfilePicture); ok {
+		// Objek yang sudah tidak ada tidak menghalangi purge
+		if err := DeleteFromCloudStorage(ctx, bucketName, objectName); err != nil {
+			slog.WarnContext(ctx, "Gagal menghapus gambar profil akun yang dihapus", "user_id", user.ID, "error", err)
+		}
+	}
+
+	return config.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, related := range []interface{}{
+			&models.Session{}, &models.LoginCode{}, &models.RecoveryCode{}, &models.UserIdentity{}, &models.EmailChange{},
+			&models.PhoneVerification{},
+		} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+		// Line sudah di-soft-delete saat akun dihapus, sehingga perlu Unscoped untuk menghapusnya permanen
+		if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&models.Line{}).Error; err != nil {
+			return err
+		}
+
+		// Nomor, nominal dan pajak invoice tetap utuh; hanya salinan data pribadi pembeli yang diganti
+		err := tx.Model(&models.Invoice{}).Where("user_id = ?", user.ID).Updates(map[string]interface{}{
+			"buyer_name":  fmt.Sprintf("deleted-%d", user.ID),
+			"buyer_email": fmt.Sprintf("deleted-%d@deleted.invalid", user.ID),
+			"buyer_phone": "",
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(user).Updates(map[string]interface{}{
+			"email":                fmt.Sprintf("deleted-%d@deleted.invalid", user.ID),
+			"username":             fmt.Sprintf("deleted-%d", user.ID),
+			"password":             "",
+			"phone_number":         "",
+			"phone_operator":       "",
+			"phone_verified":       false,
+			"profile_picture":      "",
+			"verification_code":    "",
+			"package_id":           nil,
+			"two_factor_enabled":   false,
+			"two_factor_secret":    "",
+			"two_factor_last_step": 0,
+			"purged_at":            time.Now(),
+		}).Error
+	})
+}
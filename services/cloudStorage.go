@@ -5,19 +5,27 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
+	"strings"
 
 	"cloud.google.com/go/storage"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // UploadToCloudStorage mengupload file ke Google Cloud Storage
-func UploadToCloudStorage(bucketName, objectName string, file multipart.File) error {
-	ctx := context.Background()
+func UploadToCloudStorage(ctx context.Context, bucketName, objectName string, file multipart.File) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "storage.upload",
+		attribute.String("storage.bucket", bucketName),
+		attribute.String("storage.object", objectName),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
 
 	client, err := storage.NewClient(ctx)
 	if err != nil {
-		log.Printf("Failed to create storage client: %v", err)
+		slog.ErrorContext(ctx, "Failed to create storage client", "error", err)
 		return fmt.Errorf("storage.NewClient: %v", err)
 	}
 	defer client.Close()
@@ -27,23 +35,32 @@ func UploadToCloudStorage(bucketName, objectName string, file multipart.File) er
 	wc.ContentType = "application/octet-stream"
 	wc.CacheControl = "public, max-age=86400" // Contoh header tambahan
 
-	if _, err = io.Copy(wc, file); err != nil {
-		log.Printf("Failed to copy file to GCS: %v", err)
+	written, err := io.Copy(wc, file)
+	span.SetAttributes(attribute.Int64("storage.bytes", written))
+	if err != nil {
+		metrics.StorageUploadBytes.WithLabelValues("object", "failure").Observe(float64(written))
+		slog.ErrorContext(ctx, "Failed to copy file to GCS", "error", err)
 		return fmt.Errorf("io.Copy: %v", err)
 	}
 
 	if err := wc.Close(); err != nil {
-		log.Printf("Failed to close writer: %v", err)
+		metrics.StorageUploadBytes.WithLabelValues("object", "failure").Observe(float64(written))
+		slog.ErrorContext(ctx, "Failed to close writer", "error", err)
 		return fmt.Errorf("Writer.Close: %v", err)
 	}
+	metrics.StorageUploadBytes.WithLabelValues("object", "success").Observe(float64(written))
 
-	log.Printf("File successfully uploaded to %s/%s", bucketName, objectName)
+	slog.InfoContext(ctx, "File successfully uploaded", "bucket", bucketName, "object", objectName, "bytes", written)
 	return nil
 }
 
 // GetFileFromCloudStorage mengambil file dari Google Cloud Storage
-func GetFileFromCloudStorage(bucketName, objectName string) ([]byte, error) {
-	ctx := context.Background()
+func GetFileFromCloudStorage(ctx context.Context, bucketName, objectName string) (data []byte, err error) {
+	ctx, span := tracing.StartSpan(ctx, "storage.download",
+		attribute.String("storage.bucket", bucketName),
+		attribute.String("storage.object", objectName),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
 
 	client, err := storage.NewClient(ctx)
 	if err != nil {
@@ -57,10 +74,40 @@ func GetFileFromCloudStorage(bucketName, objectName string) ([]byte, error) {
 	}
 	defer rc.Close()
 
-	data, err := io.ReadAll(rc)
+	data, err = io.ReadAll(rc)
 	if err != nil {
 		return nil, fmt.Errorf("io.ReadAll: %v", err)
 	}
 
 	return data, nil
 }
+
+// DeleteFromCloudStorage menghapus objek dari Google Cloud Storage
+func DeleteFromCloudStorage(ctx context.Context, bucketName, objectName string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "storage.delete",
+		attribute.String("storage.bucket", bucketName),
+		attribute.String("storage.object", objectName),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("gagal membuat storage client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Bucket(bucketName).Object(objectName).Delete(ctx); err != nil {
+		return fmt.Errorf("gagal menghapus objek dari cloud storage: %v", err)
+	}
+	return nil
+}
+
+// ParseStorageURL memisahkan URL publik https://storage.googleapis.com/<bucket>/<objek> menjadi nama bucket dan objek
+func ParseStorageURL(publicURL string) (bucketName, objectName string, ok bool) {
+	rest, found := strings.CutPrefix(publicURL, "https://storage.googleapis.com/")
+	if !found {
+		return "", "", false
+	}
+	bucketName, objectName, ok = strings.Cut(rest, "/")
+	return bucketName, objectName, ok && bucketName != "" && objectName != ""
+}
@@ -0,0 +1,79 @@
+// services/health.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"cloud.google.com/go/storage"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// shuttingDown bernilai true sejak sinyal shutdown diterima
+var shuttingDown atomic.Bool
+
+// MarkShuttingDown menandai server sedang dimatikan sehingga readiness bernilai false
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// IsShuttingDown melaporkan apakah server sedang dalam proses shutdown
+func IsShuttingDown() bool {
+	return shuttingDown.Load()
+}
+
+// CheckDatabase memastikan koneksi PostgreSQL dapat digunakan
+func CheckDatabase(ctx context.Context) error {
+	if config.DB == nil {
+		return errors.New("database belum terhubung")
+	}
+	sqlDB, err := config.DB.DB()
+	if err != nil {
+		return fmt.Errorf("gagal mengambil koneksi database: %v", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping database gagal: %v", err)
+	}
+	return nil
+}
+
+// CheckMigrations memastikan migrasi sudah dijalankan dan semua tabel model tersedia
+func CheckMigrations() error {
+	if !config.MigrationsApplied() {
+		return errors.New("migrasi belum dijalankan")
+	}
+	migrator := config.DB.Migrator()
+	for _, model := range config.MigratedModels() {
+		if !migrator.HasTable(model) {
+			return fmt.Errorf("tabel untuk %T belum tersedia", model)
+		}
+	}
+	return nil
+}
+
+// CheckStorage memastikan bucket Google Cloud Storage dapat dijangkau
+func CheckStorage(ctx context.Context) (err error) {
+	bucketName := os.Getenv("GCS_BUCKET_NAME")
+	if bucketName == "" {
+		return errors.New("GCS_BUCKET_NAME tidak diatur")
+	}
+
+	ctx, span := tracing.StartSpan(ctx, "storage.check", attribute.String("storage.bucket", bucketName))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("storage.NewClient: %v", err)
+	}
+	defer client.Close()
+
+	if _, err = client.Bucket(bucketName).Attrs(ctx); err != nil {
+		return fmt.Errorf("Bucket.Attrs: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// services/invoiceReceipts.go
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/invoice"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/utils"
+)
+
+const (
+	// receiptBatchSize membatasi jumlah tanda terima yang dikirim per putaran job
+	receiptBatchSize = 50
+	// maxReceiptAttempts membatasi pengiriman ulang tanda terima yang terus gagal
+	maxReceiptAttempts = 5
+)
+
+// SendInvoiceReceipts mengirimkan email tanda terima beserta PDF untuk invoice yang belum dikirim.
+// Pengiriman yang gagal dicoba lagi pada putaran berikutnya hingga maxReceiptAttempts.
+func SendInvoiceReceipts(ctx context.Context) {
+	db := config.DB.WithContext(ctx)
+
+	var invoices []models.Invoice
+	err := db.Where("receipt_sent_at IS NULL AND receipt_attempts < ?", maxReceiptAttempts).
+		Order("id").Limit(receiptBatchSize).Find(&invoices).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Gagal mencari invoice yang belum dikirim", "error", err)
+		return
+	}
+
+	for i := range invoices {
+		if ctx.Err() != nil {
+			return
+		}
+		inv := &invoices[i]
+
+		// Menandai percobaan lebih dulu agar instance lain tidak mengirim tanda terima yang sama
+		result := db.Model(&models.Invoice{}).
+			Where("id = ? AND receipt_attempts = ? AND receipt_sent_at IS NULL", inv.ID, inv.ReceiptAttempts).
+			UpdateColumn("receipt_attempts", inv.ReceiptAttempts+1)
+		if result.Error != nil || result.RowsAffected != 1 {
+			continue
+		}
+
+		err := sendInvoiceReceipt(ctx, inv)
+		if err == nil {
+			err = db.Model(&models.Invoice{}).Where("id = ?", inv.ID).UpdateColumn("receipt_sent_at", time.Now()).Error
+		}
+		if err != nil {
+			metrics.InvoicesTotal.WithLabelValues("receipt_failed").Inc()
+			slog.ErrorContext(ctx, "Gagal mengirim tanda terima invoice", "invoice", inv.Number, "attempt", inv.ReceiptAttempts+1, "error", err)
+			continue
+		}
+		metrics.InvoicesTotal.WithLabelValues("receipt_sent").Inc()
+	}
+}
+
+// sendInvoiceReceipt membuat PDF invoice lalu mengirimkannya ke email pembeli dalam bahasa pengguna
+func sendInvoiceReceipt(ctx context.Context, inv *models.Invoice) error {
+	var user models.User
+	if err := config.DB.WithContext(ctx).Unscoped().Select("id, language").Where("id = ?", inv.UserID).First(&user).Error; err != nil {
+		return err
+	}
+
+	pdf, err := invoice.RenderPDF(ctx, inv, user.Language)
+	if err != nil {
+		return err
+	}
+	return utils.SendReceiptEmail(ctx, inv.BuyerEmail, inv.Number, inv.TotalMoney().Format(), inv.PackageName,
+		utils.EmailAttachment{Name: invoice.FileName(inv), ContentType: "application/pdf", Data: pdf}, user.Language)
+}
@@ -0,0 +1,163 @@
+// services/oidc.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrOIDCProviderNotFound dikembalikan jika provider tidak terdaftar di OIDC_PROVIDERS
+	ErrOIDCProviderNotFound = errors.New("oidc provider is not configured")
+	// ErrOIDCNonceMismatch dikembalikan jika nonce pada ID token tidak sesuai dengan request login
+	ErrOIDCNonceMismatch = errors.New("oidc nonce mismatch")
+)
+
+// defaultIssuers berisi issuer bawaan untuk provider yang dikenal
+var defaultIssuers = map[string]string{
+	"google": "https://accounts.google.com",
+}
+
+// OIDCProvider membungkus konfigurasi OAuth2 dan verifier ID token untuk satu provider
+type OIDCProvider struct {
+	Name     string
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// OIDCIdentity berisi klaim identitas yang sudah diverifikasi dari ID token
+type OIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+var (
+	oidcMu        sync.Mutex
+	oidcProviders = make(map[string]*OIDCProvider)
+)
+
+// OIDCProviderNames mengembalikan daftar provider yang diaktifkan melalui OIDC_PROVIDERS
+func OIDCProviderNames() []string {
+	var names []string
+	for _, name := range config.GetEnvList("OIDC_PROVIDERS", nil) {
+		names = append(names, strings.ToLower(name))
+	}
+	return names
+}
+
+// GetOIDCProvider mengembalikan provider berdasarkan nama. Discovery issuer dilakukan saat pertama
+// kali dipakai sehingga server tetap dapat berjalan walaupun issuer sementara tidak dapat dijangkau.
+func GetOIDCProvider(ctx context.Context, name string) (*OIDCProvider, error) {
+	name = strings.ToLower(name)
+	enabled := false
+	for _, candidate := range OIDCProviderNames() {
+		if candidate == name {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	oidcMu.Lock()
+	defer oidcMu.Unlock()
+	if provider, ok := oidcProviders[name]; ok {
+		return provider, nil
+	}
+
+	prefix := "OIDC_" + strings.ToUpper(name) + "_"
+	issuer := config.GetEnv(prefix+"ISSUER", defaultIssuers[name])
+	clientID := config.GetEnv(prefix+"CLIENT_ID", "")
+	if issuer == "" || clientID == "" {
+		return nil, fmt.Errorf("%sISSUER dan %sCLIENT_ID harus diatur", prefix, prefix)
+	}
+
+	discovered, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("gagal melakukan discovery issuer %s: %w", issuer, err)
+	}
+
+	provider := &OIDCProvider{
+		Name: name,
+		oauth2: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: config.GetEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  config.GetEnv(prefix+"REDIRECT_URL", "http://localhost:8080/auth/oidc/"+name+"/callback"),
+			Endpoint:     discovered.Endpoint(),
+			Scopes:       config.GetEnvList(prefix+"SCOPES", []string{oidc.ScopeOpenID, "email", "profile"}),
+		},
+		verifier: discovered.Verifier(&oidc.Config{ClientID: clientID}),
+	}
+	oidcProviders[name] = provider
+	return provider, nil
+}
+
+// AuthCodeURL membuat URL otorisasi dengan state, nonce dan PKCE (S256)
+func (p *OIDCProvider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange menukar authorization code dengan token, lalu memverifikasi ID token dan nonce-nya
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (identity *OIDCIdentity, err error) {
+	ctx, span := tracing.StartSpan(ctx, "oidc.exchange", attribute.String("oidc.provider", p.Name))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response does not contain an id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrOIDCNonceMismatch
+	}
+
+	var claims struct {
+		Email         string      `json:"email"`
+		EmailVerified interface{} `json:"email_verified"`
+		Name          string      `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &OIDCIdentity{
+		Subject:       idToken.Subject,
+		Email:         strings.ToLower(strings.TrimSpace(claims.Email)),
+		EmailVerified: isTrueClaim(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// isTrueClaim menerima email_verified sebagai boolean maupun string karena sebagian provider
+// mengirimkannya sebagai "true"
+func isTrueClaim(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,28 @@
+// services/orderExpiry.go
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+)
+
+// ExpirePendingOrders menandai order yang belum dibayar hingga batas waktunya sebagai expired.
+// Notifikasi lunas yang datang setelahnya tetap diproses oleh webhook pembayaran.
+func ExpirePendingOrders(ctx context.Context) {
+	result := config.DB.WithContext(ctx).Model(&models.Order{}).
+		Where("status = ? AND expires_at < ?", models.OrderPending, time.Now()).
+		Update("status", models.OrderExpired)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Gagal menandai order kedaluwarsa", "error", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		metrics.OrdersTotal.WithLabelValues(models.OrderExpired).Add(float64(result.RowsAffected))
+		slog.InfoContext(ctx, "Order kedaluwarsa ditandai", "count", result.RowsAffected)
+	}
+}
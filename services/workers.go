@@ -0,0 +1,71 @@
+// services/workers.go
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Pengelola goroutine latar belakang (job terjadwal, pengiriman email, dsb.)
+// agar semuanya dapat dihentikan dengan rapi saat shutdown.
+var (
+	workersCtx, stopWorkers = context.WithCancel(context.Background())
+	workersWG               sync.WaitGroup
+)
+
+// StartWorker menjalankan fn pada goroutine terpisah. Context yang diberikan akan
+// dibatalkan ketika StopWorkers dipanggil, dan fn harus berhenti setelahnya.
+func StartWorker(name string, fn func(ctx context.Context)) {
+	workersWG.Add(1)
+	go func() {
+		defer workersWG.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("Worker panic", "worker", name, "panic", r)
+			}
+		}()
+		fn(workersCtx)
+	}()
+}
+
+// StartPeriodicWorker menjalankan fn setiap interval (dan sekali saat dimulai) sampai worker dihentikan.
+// Interval nol atau negatif menonaktifkan worker.
+func StartPeriodicWorker(name string, interval time.Duration, fn func(ctx context.Context)) {
+	if interval <= 0 {
+		slog.Warn("Worker dinonaktifkan karena interval tidak positif", "worker", name, "interval", interval)
+		return
+	}
+	StartWorker(name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fn(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// StopWorkers membatalkan context semua worker dan menunggu hingga selesai
+// atau hingga ctx berakhir
+func StopWorkers(ctx context.Context) error {
+	stopWorkers()
+
+	done := make(chan struct{})
+	go func() {
+		workersWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,44 @@
+// sms/console.go
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// WriterSender menuliskan SMS ke writer alih-alih mengirimkannya, sebagai pengganti gateway
+// saat pengembangan. Kode OTP ikut tertulis, jadi jangan dipakai di production.
+type WriterSender struct {
+	name string
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+// NewConsoleSender menuliskan SMS ke stdout
+func NewConsoleSender() *WriterSender {
+	return &WriterSender{name: "console", w: os.Stdout}
+}
+
+// NewFileSender menambahkan SMS ke akhir file di path, misalnya untuk dibaca oleh test end-to-end
+func NewFileSender(path string) (*WriterSender, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &WriterSender{name: "file", w: file}, nil
+}
+
+func (s *WriterSender) Name() string {
+	return s.name
+}
+
+func (s *WriterSender) Send(_ context.Context, to, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprintf(s.w, "%s SMS to %s: %s\n", time.Now().UTC().Format(time.RFC3339), to, body)
+	return err
+}
@@ -0,0 +1,93 @@
+// sms/sms.go
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/config"
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Sender mengirimkan SMS melalui satu gateway. Implementasinya harus aman dipakai secara bersamaan.
+type Sender interface {
+	// Name mengembalikan nama provider untuk label metrik dan log
+	Name() string
+	// Send mengirimkan pesan teks ke nomor tujuan dalam format E.164
+	Send(ctx context.Context, to, body string) error
+}
+
+var defaultSender Sender = NewConsoleSender()
+
+// Init memilih provider SMS dari environment (SMS_PROVIDER=console|file|twilio)
+func Init() error {
+	provider := strings.ToLower(strings.TrimSpace(config.GetEnv("SMS_PROVIDER", "")))
+	if provider == "" {
+		// Provider console menulis kode OTP ke log, jadi hanya dipakai otomatis saat pengembangan
+		if !config.IsDevelopment() {
+			return errors.New("SMS_PROVIDER harus diatur; provider console hanya dipakai otomatis jika APP_ENV=development")
+		}
+		provider = "console"
+	}
+
+	switch provider {
+	case "console":
+		defaultSender = NewConsoleSender()
+	case "file":
+		sender, err := NewFileSender(config.GetEnv("SMS_FILE_PATH", "sms.log"))
+		if err != nil {
+			return fmt.Errorf("gagal membuka file SMS: %v", err)
+		}
+		defaultSender = sender
+	case "twilio":
+		sender, err := NewTwilioSender(
+			config.GetEnv("TWILIO_ACCOUNT_SID", ""),
+			config.GetEnv("TWILIO_AUTH_TOKEN", ""),
+			config.GetEnv("TWILIO_FROM", ""),
+			config.GetEnvDuration("SMS_HTTP_TIMEOUT", 10*time.Second),
+		)
+		if err != nil {
+			return err
+		}
+		defaultSender = sender
+	default:
+		return fmt.Errorf("SMS_PROVIDER tidak dikenal: %s", provider)
+	}
+
+	if name := defaultSender.Name(); name == "console" || name == "file" {
+		slog.Warn("SMS tidak dikirim ke gateway, hanya ditulis untuk pengembangan", "provider", name)
+	}
+	return nil
+}
+
+// SendPhoneVerificationCode mengirimkan kode OTP verifikasi nomor telepon dalam bahasa pengguna
+func SendPhoneVerificationCode(ctx context.Context, to, code string, validFor time.Duration, lang string) error {
+	return send(ctx, "phone_verification", to, i18n.T(lang, i18n.MsgPhoneVerificationSMS, code, int(validFor.Minutes())))
+}
+
+// send mengirimkan SMS melalui provider aktif dan mencatat metrik serta span per jenis pesan
+func send(ctx context.Context, smsType, to, body string) (err error) {
+	provider := defaultSender.Name()
+	ctx, span := tracing.StartSpan(ctx, "sms.send",
+		attribute.String("sms.type", smsType),
+		attribute.String("sms.provider", provider),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	err = defaultSender.Send(ctx, to, body)
+	metrics.SMSSentTotal.WithLabelValues(smsType, provider, metrics.Result(err)).Inc()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to send SMS", "type", smsType, "provider", provider, "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "SMS sent", "type", smsType, "provider", provider)
+	return nil
+}
@@ -0,0 +1,79 @@
+// sms/twilio.go
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const twilioAPIBase = "https://api.twilio.com/2010-04-01"
+
+// TwilioSender mengirimkan SMS melalui Twilio Programmable Messaging API
+type TwilioSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// NewTwilioSender membuat sender Twilio. from dapat berupa nomor E.164 atau Messaging Service SID (MG...).
+func NewTwilioSender(accountSID, authToken, from string, timeout time.Duration) (*TwilioSender, error) {
+	if accountSID == "" || authToken == "" || from == "" {
+		return nil, errors.New("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN dan TWILIO_FROM harus diatur untuk provider twilio")
+	}
+	return &TwilioSender{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (s *TwilioSender) Name() string {
+	return "twilio"
+}
+
+func (s *TwilioSender) Send(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "Body": {body}}
+	if strings.HasPrefix(s.from, "MG") {
+		form.Set("MessagingServiceSid", s.from)
+	} else {
+		form.Set("From", s.from)
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioAPIBase, url.PathEscape(s.accountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		// Twilio mengembalikan kode dan pesan error dalam JSON
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio: %s (code %d, status %d)", apiErr.Message, apiErr.Code, resp.StatusCode)
+		}
+		return fmt.Errorf("twilio: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// utils/breachedPasswords.go
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// breachedPrefixLength adalah panjang prefix hash SHA-1 yang dipakai model k-anonymity haveibeenpwned
+const breachedPrefixLength = 5
+
+var (
+	breachedMu  sync.RWMutex
+	breachedDir string
+	// breachedRanges memetakan prefix hash ke kumpulan suffix, dipakai jika daftar berupa satu file
+	breachedRanges map[string]map[string]struct{}
+)
+
+// LoadBreachedPasswords memuat daftar hash SHA-1 password yang pernah bocor. path dapat berupa:
+//   - direktori berisi satu file per prefix 5 karakter (misalnya 5BAA6.txt) dengan baris "SUFFIX:COUNT",
+//     seperti keluaran haveibeenpwned-downloader. File hanya dibaca saat prefix tersebut diperiksa.
+//   - satu file berisi baris "HASH:COUNT" dengan hash lengkap, yang dimuat ke memori dan dikelompokkan per prefix.
+//
+// path kosong menonaktifkan pemeriksaan.
+func LoadBreachedPasswords(path string) error {
+	breachedMu.Lock()
+	defer breachedMu.Unlock()
+	breachedDir, breachedRanges = "", nil
+	if path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		breachedDir = path
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ranges := make(map[string]map[string]struct{})
+	err = scanBreachedHashes(file, func(hash string) {
+		if len(hash) != sha1.Size*2 {
+			return
+		}
+		prefix, suffix := hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+		if ranges[prefix] == nil {
+			ranges[prefix] = make(map[string]struct{})
+		}
+		ranges[prefix][suffix] = struct{}{}
+	})
+	if err != nil {
+		return err
+	}
+	breachedRanges = ranges
+	return nil
+}
+
+// IsBreachedPassword memeriksa apakah password terdapat pada daftar password bocor.
+// Mengembalikan false jika daftar tidak dimuat.
+func IsBreachedPassword(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+
+	breachedMu.RLock()
+	dir, ranges := breachedDir, breachedRanges
+	breachedMu.RUnlock()
+
+	if ranges != nil {
+		_, found := ranges[prefix][suffix]
+		return found, nil
+	}
+	if dir == "" {
+		return false, nil
+	}
+
+	file, err := openBreachedRange(dir, prefix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	found := false
+	err = scanBreachedHashes(file, func(candidate string) {
+		if candidate == suffix {
+			found = true
+		}
+	})
+	return found, err
+}
+
+// openBreachedRange membuka file rentang untuk prefix, dengan atau tanpa ekstensi .txt
+func openBreachedRange(dir, prefix string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.Open(filepath.Join(dir, prefix))
+	}
+	return file, err
+}
+
+// scanBreachedHashes membaca baris "HASH[:COUNT]" dan memanggil fn dengan hash dalam huruf besar
+func scanBreachedHashes(r io.Reader, fn func(hash string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if hash != "" {
+			fn(strings.ToUpper(hash))
+		}
+	}
+	return scanner.Err()
+}
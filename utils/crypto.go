@@ -0,0 +1,165 @@
+// utils/crypto.go
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// encryptedPrefix menandai versi format ciphertext agar kunci atau algoritma dapat diganti kelak
+const encryptedPrefix = "v1:"
+
+// encryptionKey mengambil kunci AES-256 dari SECRET_ENCRYPTION_KEY, atau JWT_SECRET jika kosong
+func encryptionKey() ([]byte, error) {
+	secret := os.Getenv("SECRET_ENCRYPTION_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		return nil, ErrSecretNotSet
+	}
+	key := sha256.Sum256([]byte(secret))
+	return key[:], nil
+}
+
+// EncryptSecret mengenkripsi data sensitif (misalnya secret TOTP) dengan AES-256-GCM sebelum disimpan
+func EncryptSecret(plaintext string) (string, error) {
+	key, err := encryptionKey()
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret membuka data yang dienkripsi oleh EncryptSecret
+func DecryptSecret(ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, encryptedPrefix) {
+		return "", errors.New("unknown encrypted secret format")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(ciphertext, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	key, err := encryptionKey()
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	nonce, data := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// HashToken mengembalikan hash SHA-256 (hex) untuk token acak berentropi tinggi seperti recovery code.
+// Token semacam ini tidak perlu hash lambat seperti bcrypt karena tidak dapat ditebak dengan kamus.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ErrSealedTokenExpired dikembalikan OpenToken untuk token yang sudah kedaluwarsa
+var ErrSealedTokenExpired = errors.New("sealed token has expired")
+
+type sealedEnvelope struct {
+	ExpiresAt int64           `json:"exp"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// SealToken mengenkripsi payload menjadi token opak berumur pendek, misalnya untuk state OAuth
+// yang disimpan di cookie. Isinya tidak dapat dibaca maupun diubah oleh klien.
+func SealToken(payload interface{}, ttl time.Duration) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	envelope, err := json.Marshal(sealedEnvelope{ExpiresAt: time.Now().Add(ttl).Unix(), Payload: data})
+	if err != nil {
+		return "", err
+	}
+	sealed, err := EncryptSecret(string(envelope))
+	if err != nil {
+		return "", err
+	}
+	// Menggunakan alfabet URL-safe agar token aman dipakai di cookie dan query string
+	return base64.RawURLEncoding.EncodeToString([]byte(sealed)), nil
+}
+
+// OpenToken membuka token dari SealToken ke dst dan memeriksa masa berlakunya
+func OpenToken(token string, dst interface{}) error {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	plaintext, err := DecryptSecret(string(sealed))
+	if err != nil {
+		return err
+	}
+
+	var envelope sealedEnvelope
+	if err := json.Unmarshal([]byte(plaintext), &envelope); err != nil {
+		return err
+	}
+	if time.Now().Unix() > envelope.ExpiresAt {
+		return ErrSealedTokenExpired
+	}
+	return json.Unmarshal(envelope.Payload, dst)
+}
+
+// RandomToken membuat string acak URL-safe dari n byte entropi, misalnya untuk state dan nonce OAuth
+func RandomToken(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// GenerateNumericCode membuat kode angka acak sepanjang digits (misalnya OTP SMS) tanpa bias modulo
+func GenerateNumericCode(digits int) (string, error) {
+	n, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
@@ -2,12 +2,19 @@
 package utils
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/mfuadfakhruzzaki/backend-api/i18n"
+	"github.com/mfuadfakhruzzaki/backend-api/metrics"
+	"github.com/mfuadfakhruzzaki/backend-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/gomail.v2"
 )
 
@@ -26,7 +33,35 @@ func GenerateVerificationCode() (string, error) {
 }
 
 // SendVerificationEmail mengirimkan email verifikasi dengan kode ke pengguna menggunakan gomail.v2
-func SendVerificationEmail(recipientEmail string, verificationCode string) error {
+// dalam bahasa yang dipilih pengguna
+func SendVerificationEmail(ctx context.Context, recipientEmail string, verificationCode string, lang string) error {
+	return sendEmail(ctx, "verification", recipientEmail,
+		i18n.T(lang, i18n.MsgVerificationEmailSubject),
+		i18n.T(lang, i18n.MsgVerificationEmailBody, verificationCode))
+}
+
+// SendLoginCodeEmail mengirimkan kode login sekali pakai dan, jika tersedia, magic link
+// untuk login tanpa password
+func SendLoginCodeEmail(ctx context.Context, recipientEmail, code, link string, validFor time.Duration, lang string) error {
+	body := i18n.T(lang, i18n.MsgLoginCodeEmailBody, code, int(validFor.Minutes()))
+	if link != "" {
+		body += "\n\n" + i18n.T(lang, i18n.MsgLoginCodeEmailLink, link)
+	}
+	return sendEmail(ctx, "login_code", recipientEmail, i18n.T(lang, i18n.MsgLoginCodeEmailSubject), body)
+}
+
+// EmailAttachment adalah file yang dilampirkan pada email
+type EmailAttachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// sendEmail mengirimkan email teks biasa melalui SMTP dan mencatat metrik serta span per jenis email
+func sendEmail(ctx context.Context, emailType, recipientEmail, subject, body string, attachments ...EmailAttachment) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "smtp.send", attribute.String("email.type", emailType))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	// Mengambil konfigurasi SMTP dari environment variables
 	smtpHost := os.Getenv("SMTP_HOST")
 	smtpPort := os.Getenv("SMTP_PORT")
@@ -35,6 +70,7 @@ func SendVerificationEmail(recipientEmail string, verificationCode string) error
 
 	// Memeriksa apakah semua konfigurasi SMTP telah diatur
 	if smtpHost == "" || smtpPort == "" || senderEmail == "" || smtpPassword == "" {
+		metrics.EmailsSentTotal.WithLabelValues(emailType, "failure").Inc()
 		return fmt.Errorf("SMTP configuration is missing in environment variables")
 	}
 
@@ -42,15 +78,23 @@ func SendVerificationEmail(recipientEmail string, verificationCode string) error
 	m := gomail.NewMessage()
 	m.SetHeader("From", senderEmail)
 	m.SetHeader("To", recipientEmail)
-	m.SetHeader("Subject", "Email Verification for Data Quota Tracker")
-	m.SetBody("text/plain", fmt.Sprintf(
-		"Welcome to Data Quota Tracker!\n\nYour verification code is: %s\n\nPlease enter this code to verify your email and start using the app.",
-		verificationCode))
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/plain", body)
+	for _, attachment := range attachments {
+		data := attachment.Data
+		m.Attach(attachment.Name,
+			gomail.SetHeader(map[string][]string{"Content-Type": {attachment.ContentType}}),
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}))
+	}
 
 	// Mengonversi SMTP_PORT dari string ke integer
 	port, err := strconv.Atoi(smtpPort)
 	if err != nil {
-		log.Printf("Invalid SMTP port: %v", err)
+		metrics.EmailsSentTotal.WithLabelValues(emailType, "failure").Inc()
+		slog.ErrorContext(ctx, "Invalid SMTP port", "error", err)
 		return fmt.Errorf("invalid SMTP port: %v", err)
 	}
 
@@ -63,11 +107,50 @@ func SendVerificationEmail(recipientEmail string, verificationCode string) error
 	}
 
 	// Kirim email
-	if err := d.DialAndSend(m); err != nil {
-		log.Printf("Failed to send verification email to %s: %v", recipientEmail, err)
+	err = d.DialAndSend(m)
+	metrics.EmailsSentTotal.WithLabelValues(emailType, metrics.Result(err)).Inc()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to send email", "type", emailType, "recipient", recipientEmail, "error", err)
 		return err
 	}
 
-	log.Printf("Verification email sent to %s with code %s\n", recipientEmail, verificationCode)
+	slog.InfoContext(ctx, "Email sent", "type", emailType, "recipient", recipientEmail)
 	return nil
 }
+
+// SendNewDeviceEmail memberi tahu pengguna bahwa akunnya baru saja digunakan untuk login dari perangkat baru
+func SendNewDeviceEmail(ctx context.Context, recipientEmail, device, ipAddress string, at time.Time, lang string) error {
+	return sendEmail(ctx, "new_device", recipientEmail,
+		i18n.T(lang, i18n.MsgNewDeviceEmailSubject),
+		i18n.T(lang, i18n.MsgNewDeviceEmailBody, device, ipAddress, at.UTC().Format("2006-01-02 15:04 MST")))
+}
+
+// SendEmailChangeCodeEmail mengirimkan kode konfirmasi ke alamat email baru
+func SendEmailChangeCodeEmail(ctx context.Context, recipientEmail, code string, validFor time.Duration, lang string) error {
+	return sendEmail(ctx, "email_change_code", recipientEmail,
+		i18n.T(lang, i18n.MsgEmailChangeCodeSubject),
+		i18n.T(lang, i18n.MsgEmailChangeCodeBody, code, int(validFor.Minutes())))
+}
+
+// SendEmailChangedNotice memberi tahu alamat email lama bahwa email akun telah diganti, beserta
+// tautan (atau token) untuk membatalkannya
+func SendEmailChangedNotice(ctx context.Context, recipientEmail, newEmail, undo string, undoValidFor time.Duration, lang string) error {
+	return sendEmail(ctx, "email_changed", recipientEmail,
+		i18n.T(lang, i18n.MsgEmailChangedNoticeSubject),
+		i18n.T(lang, i18n.MsgEmailChangedNoticeBody, newEmail, int(undoValidFor.Hours()), undo))
+}
+
+// SendAccountDeletedEmail mengonfirmasi penghapusan akun beserta masa tenggang sebelum data dihapus permanen
+func SendAccountDeletedEmail(ctx context.Context, recipientEmail string, gracePeriod time.Duration, lang string) error {
+	return sendEmail(ctx, "account_deleted", recipientEmail,
+		i18n.T(lang, i18n.MsgAccountDeletedEmailSubject),
+		i18n.T(lang, i18n.MsgAccountDeletedEmailBody, int(gracePeriod.Hours()/24)))
+}
+
+// SendReceiptEmail mengirimkan tanda terima pembayaran dengan PDF invoice sebagai lampiran
+func SendReceiptEmail(ctx context.Context, recipientEmail, invoiceNumber, total, packageName string, invoicePDF EmailAttachment, lang string) error {
+	return sendEmail(ctx, "receipt", recipientEmail,
+		i18n.T(lang, i18n.MsgReceiptEmailSubject, invoiceNumber),
+		i18n.T(lang, i18n.MsgReceiptEmailBody, total, packageName, invoiceNumber),
+		invoicePDF)
+}
@@ -1,14 +1,155 @@
 // utils/hash.go
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+    "crypto/rand"
+    "crypto/subtle"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
 
+    "golang.org/x/crypto/argon2"
+    "golang.org/x/crypto/bcrypt"
+)
+
+const (
+    // AlgorithmArgon2id dan AlgorithmBcrypt adalah nilai PASSWORD_HASH_ALGORITHM yang didukung
+    AlgorithmArgon2id = "argon2id"
+    AlgorithmBcrypt   = "bcrypt"
+
+    argon2SaltLength = 16
+    argon2KeyLength  = 32
+)
+
+// ErrInvalidHash dikembalikan jika hash password tidak dikenali formatnya
+var ErrInvalidHash = errors.New("invalid password hash format")
+
+// Argon2Params berisi parameter Argon2id. Memory dalam KiB.
+type Argon2Params struct {
+    Memory  uint32
+    Time    uint32
+    Threads uint8
+}
+
+var (
+    dummyHash     string
+    dummyHashOnce sync.Once
+)
+
+// HashAlgorithm mengembalikan algoritma hash untuk password baru dari PASSWORD_HASH_ALGORITHM
+// (bawaan argon2id). bcrypt hanya disediakan untuk rollback.
+func HashAlgorithm() string {
+    if strings.EqualFold(os.Getenv("PASSWORD_HASH_ALGORITHM"), AlgorithmBcrypt) {
+        return AlgorithmBcrypt
+    }
+    return AlgorithmArgon2id
+}
+
+// CurrentArgon2Params membaca parameter Argon2id dari environment. Nilai bawaan mengikuti
+// rekomendasi OWASP (19 MiB, 2 iterasi, 1 thread).
+func CurrentArgon2Params() Argon2Params {
+    return Argon2Params{
+        Memory:  uint32(envUint("ARGON2_MEMORY_KIB", 19*1024, 32)),
+        Time:    uint32(envUint("ARGON2_ITERATIONS", 2, 32)),
+        Threads: uint8(envUint("ARGON2_THREADS", 1, 8)),
+    }
+}
+
+// BcryptCost membaca cost bcrypt untuk password baru dari BCRYPT_COST
+func BcryptCost() int {
+    return int(envUint("BCRYPT_COST", 12, 31))
+}
+
+// HashPassword membuat hash password dengan algoritma yang sedang dikonfigurasi
 func HashPassword(password string) (string, error) {
-    bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-    return string(bytes), err
+    if HashAlgorithm() == AlgorithmBcrypt {
+        bytes, err := bcrypt.GenerateFromPassword([]byte(password), BcryptCost())
+        return string(bytes), err
+    }
+    return HashArgon2id(password, CurrentArgon2Params())
 }
 
+// HashArgon2id membuat hash Argon2id dalam format PHC: $argon2id$v=19$m=...,t=...,p=...$salt$hash
+func HashArgon2id(password string, params Argon2Params) (string, error) {
+    salt := make([]byte, argon2SaltLength)
+    if _, err := rand.Read(salt); err != nil {
+        return "", err
+    }
+    key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, argon2KeyLength)
+    return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.Memory, params.Time, params.Threads,
+        base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// CheckPasswordHash memverifikasi password terhadap hash Argon2id maupun bcrypt lama
 func CheckPasswordHash(password, hash string) bool {
+    if strings.HasPrefix(hash, "$argon2id$") {
+        params, salt, key, err := decodeArgon2id(hash)
+        if err != nil {
+            return false
+        }
+        candidate := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+        return subtle.ConstantTimeCompare(candidate, key) == 1
+    }
     err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
     return err == nil
 }
+
+// NeedsRehash mengembalikan true jika hash dibuat dengan algoritma atau parameter yang berbeda dari
+// konfigurasi saat ini, sehingga perlu diperbarui setelah login berhasil
+func NeedsRehash(hash string) bool {
+    if hash == "" {
+        return false
+    }
+    if HashAlgorithm() == AlgorithmBcrypt {
+        cost, err := bcrypt.Cost([]byte(hash))
+        return err != nil || cost != BcryptCost()
+    }
+    params, _, _, err := decodeArgon2id(hash)
+    return err != nil || params != CurrentArgon2Params()
+}
+
+// CheckDummyPassword menjalankan perbandingan hash terhadap hash palsu agar waktu respons
+// login untuk email yang tidak terdaftar sama dengan email yang terdaftar
+func CheckDummyPassword(password string) {
+    dummyHashOnce.Do(func() {
+        dummyHash, _ = HashPassword("dummy-password")
+    })
+    _ = CheckPasswordHash(password, dummyHash)
+}
+
+// decodeArgon2id mengurai hash Argon2id dalam format PHC
+func decodeArgon2id(hash string) (params Argon2Params, salt, key []byte, err error) {
+    parts := strings.Split(hash, "$")
+    if len(parts) != 6 || parts[1] != "argon2id" {
+        return params, nil, nil, ErrInvalidHash
+    }
+
+    var version int
+    if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+        return params, nil, nil, ErrInvalidHash
+    }
+    if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+        return params, nil, nil, ErrInvalidHash
+    }
+
+    if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+        return params, nil, nil, ErrInvalidHash
+    }
+    if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+        return params, nil, nil, ErrInvalidHash
+    }
+    return params, salt, key, nil
+}
+
+// envUint membaca bilangan bulat positif dari environment dengan batas bit tertentu
+func envUint(key string, fallback uint64, bits int) uint64 {
+    value, err := strconv.ParseUint(os.Getenv(key), 10, bits)
+    if err != nil || value == 0 {
+        return fallback
+    }
+    return value
+}
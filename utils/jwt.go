@@ -11,17 +11,26 @@ import (
 )
 
 var (
-	ErrSecretNotSet = errors.New("JWT_SECRET is not set in the environment")
+	ErrSecretNotSet      = errors.New("JWT_SECRET is not set in the environment")
+	ErrWrongTokenPurpose = errors.New("token cannot be used for this purpose")
 )
 
+// AccessTokenTTL adalah masa berlaku access token dan sesi yang terkait
+const AccessTokenTTL = 72 * time.Hour
+
+// Purpose2FA menandai token tantangan login yang menunggu kode 2FA
+const Purpose2FA = "2fa"
+
 // Claims defines the structure for JWT claims
 type Claims struct {
 	Email string `json:"email"`
+	// Purpose kosong untuk access token; token dengan purpose lain hanya berlaku di endpoint tertentu
+	Purpose string `json:"purpose,omitempty"`
 	jwt.StandardClaims
 }
 
-// GenerateJWT membuat token JWT berdasarkan email pengguna
-func GenerateJWT(email string) (string, error) {
+// GenerateJWT membuat token JWT berdasarkan email pengguna dan ID sesi (klaim jti)
+func GenerateJWT(email, sessionID string) (string, error) {
 	secretKey := os.Getenv("JWT_SECRET")
 	if secretKey == "" {
 		return "", ErrSecretNotSet
@@ -32,7 +41,8 @@ func GenerateJWT(email string) (string, error) {
 	claims := &Claims{
 		Email: email,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(72 * time.Hour).Unix(),
+			Id:        sessionID,
+			ExpiresAt: time.Now().Add(AccessTokenTTL).Unix(),
 			IssuedAt:  time.Now().Unix(),
 			Issuer:    "your-app-name", // Ganti dengan nama aplikasi Anda
 		},
@@ -47,15 +57,14 @@ func GenerateJWT(email string) (string, error) {
 		return "", err
 	}
 
-	fmt.Println("Token generated:", tokenString) // Output debugging
 	return tokenString, nil
 }
 
-// ValidateToken memvalidasi token JWT dan mengembalikan email pengguna jika valid
-func ValidateToken(tokenString string) (string, error) {
+// ValidateToken memvalidasi token JWT dan mengembalikan klaimnya jika valid
+func ValidateToken(tokenString string) (*Claims, error) {
 	secretKey := os.Getenv("JWT_SECRET")
 	if secretKey == "" {
-		return "", ErrSecretNotSet
+		return nil, ErrSecretNotSet
 	}
 	mySigningKey := []byte(secretKey)
 
@@ -70,13 +79,63 @@ func ValidateToken(tokenString string) (string, error) {
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if !token.Valid {
-		return "", errors.New("invalid token")
+		return nil, errors.New("invalid token")
+	}
+
+	// Token khusus (misalnya tantangan 2FA) tidak boleh dipakai sebagai access token
+	if claims.Purpose != "" {
+		return nil, ErrWrongTokenPurpose
+	}
+
+	// Token valid, kembalikan klaim
+	return claims, nil
+}
+
+// GeneratePurposeToken membuat token berumur pendek yang hanya berlaku untuk purpose tertentu
+func GeneratePurposeToken(email, purpose string, ttl time.Duration) (string, error) {
+	secretKey := os.Getenv("JWT_SECRET")
+	if secretKey == "" {
+		return "", ErrSecretNotSet
 	}
 
-	// Token valid, kembalikan email
+	claims := &Claims{
+		Email:   email,
+		Purpose: purpose,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(ttl).Unix(),
+			IssuedAt:  time.Now().Unix(),
+			Issuer:    "your-app-name",
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+}
+
+// ValidatePurposeToken memvalidasi token dengan purpose tertentu dan mengembalikan email pengguna
+func ValidatePurposeToken(tokenString, purpose string) (string, error) {
+	secretKey := os.Getenv("JWT_SECRET")
+	if secretKey == "" {
+		return "", ErrSecretNotSet
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secretKey), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if !token.Valid {
+		return "", errors.New("invalid token")
+	}
+	if claims.Purpose != purpose {
+		return "", ErrWrongTokenPurpose
+	}
 	return claims.Email, nil
 }
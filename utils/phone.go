@@ -0,0 +1,88 @@
+// utils/phone.go
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhoneNumber dikembalikan jika nomor bukan nomor seluler Indonesia yang valid
+var ErrInvalidPhoneNumber = errors.New("invalid Indonesian mobile phone number")
+
+// Nama operator seluler yang dapat dideteksi dari prefix nomor
+const (
+	OperatorTelkomsel = "telkomsel"
+	OperatorIndosat   = "indosat"
+	OperatorXL        = "xl"
+	OperatorAxis      = "axis"
+	OperatorTri       = "tri"
+	OperatorSmartfren = "smartfren"
+)
+
+// Operators berisi semua operator yang dikenal, untuk validasi filter paket
+var Operators = []string{OperatorTelkomsel, OperatorIndosat, OperatorXL, OperatorAxis, OperatorTri, OperatorSmartfren}
+
+// operatorPrefixes memetakan prefix nomor seluler (tanpa 0 atau 62 di depan) ke operatornya
+var operatorPrefixes = map[string]string{
+	"811": OperatorTelkomsel, "812": OperatorTelkomsel, "813": OperatorTelkomsel,
+	"821": OperatorTelkomsel, "822": OperatorTelkomsel, "823": OperatorTelkomsel,
+	"851": OperatorTelkomsel, "852": OperatorTelkomsel, "853": OperatorTelkomsel,
+
+	"814": OperatorIndosat, "815": OperatorIndosat, "816": OperatorIndosat,
+	"855": OperatorIndosat, "856": OperatorIndosat, "857": OperatorIndosat, "858": OperatorIndosat,
+
+	"817": OperatorXL, "818": OperatorXL, "819": OperatorXL,
+	"859": OperatorXL, "877": OperatorXL, "878": OperatorXL,
+
+	"831": OperatorAxis, "832": OperatorAxis, "833": OperatorAxis, "838": OperatorAxis,
+
+	"895": OperatorTri, "896": OperatorTri, "897": OperatorTri, "898": OperatorTri, "899": OperatorTri,
+
+	"881": OperatorSmartfren, "882": OperatorSmartfren, "883": OperatorSmartfren,
+	"884": OperatorSmartfren, "885": OperatorSmartfren, "886": OperatorSmartfren,
+	"887": OperatorSmartfren, "888": OperatorSmartfren, "889": OperatorSmartfren,
+}
+
+// NormalizePhoneNumber mengubah nomor seluler Indonesia (08..., 628... atau +628..., boleh dengan spasi,
+// tanda hubung, titik atau kurung) ke format E.164, misalnya +6281234567890
+func NormalizePhoneNumber(raw string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+
+	var national string
+	switch {
+	case strings.HasPrefix(digits, "+62"):
+		national = digits[3:]
+	case strings.HasPrefix(digits, "62"):
+		national = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		national = digits[1:]
+	default:
+		return "", ErrInvalidPhoneNumber
+	}
+
+	// Nomor seluler diawali 8 dan terdiri dari 9 sampai 12 digit setelah kode negara
+	if !strings.HasPrefix(national, "8") || len(national) < 9 || len(national) > 12 {
+		return "", ErrInvalidPhoneNumber
+	}
+	for _, r := range national {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidPhoneNumber
+		}
+	}
+	return "+62" + national, nil
+}
+
+// DetectOperator mengembalikan operator seluler dari nomor E.164, atau string kosong jika prefix tidak dikenal
+func DetectOperator(e164 string) string {
+	national := strings.TrimPrefix(e164, "+62")
+	if len(national) < 3 || national == e164 {
+		return ""
+	}
+	return operatorPrefixes[national[:3]]
+}
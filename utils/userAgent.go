@@ -0,0 +1,77 @@
+// utils/userAgent.go
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// uaMatcher memetakan penanda pada User-Agent ke nama yang mudah dibaca. Urutan penting karena
+// banyak browser menyertakan penanda browser lain (misalnya Edge juga menyebut Chrome dan Safari).
+type uaMatcher struct {
+	token string
+	name  string
+}
+
+var (
+	uaBrowsers = []uaMatcher{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"okhttp/", "Android app"},
+		{"Dart/", "Mobile app"},
+		{"PostmanRuntime/", "Postman"},
+		{"curl/", "curl"},
+	}
+	uaSystems = []uaMatcher{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// DescribeUserAgent membuat nama perangkat singkat seperti "Chrome on Android" dari header User-Agent
+func DescribeUserAgent(userAgent string) string {
+	browser := matchUserAgent(userAgent, uaBrowsers)
+	system := matchUserAgent(userAgent, uaSystems)
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
+
+func matchUserAgent(userAgent string, matchers []uaMatcher) string {
+	for _, m := range matchers {
+		if strings.Contains(userAgent, m.token) {
+			return m.name
+		}
+	}
+	return ""
+}
+
+// SanitizeHeaderValue menyiapkan nilai header (misalnya User-Agent) untuk disimpan di kolom teks: byte UTF-8
+// yang tidak valid dan NUL dibuang, lalu dipotong menjadi maksimal maxRunes karakter tanpa memecah karakter
+// multi-byte. PostgreSQL menolak string yang bukan UTF-8 valid.
+func SanitizeHeaderValue(value string, maxRunes int) string {
+	value = strings.ReplaceAll(strings.ToValidUTF8(value, ""), "\x00", "")
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) <= maxRunes {
+		return value
+	}
+	runes := []rune(value)
+	return strings.TrimSpace(string(runes[:maxRunes]))
+}
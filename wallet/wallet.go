@@ -0,0 +1,267 @@
+// wallet/wallet.go
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/backend-api/models"
+	"github.com/mfuadfakhruzzaki/backend-api/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInsufficientFunds dikembalikan jika saldo wallet tidak cukup untuk transaksi
+	ErrInsufficientFunds = errors.New("insufficient wallet balance")
+	// ErrInvalidAmount dikembalikan untuk nominal nol atau bertanda salah
+	ErrInvalidAmount = errors.New("invalid wallet amount")
+	// ErrDuplicateJournal dikembalikan jika jurnal dengan jenis dan referensi yang sama sudah dicatat
+	ErrDuplicateJournal = errors.New("journal entry already recorded")
+)
+
+// Akun sistem yang menjadi lawan transaksi wallet
+const (
+	// accountGateway menampung dana yang diterima melalui payment gateway (top-up, dan refund order yang
+	// dibayar melalui gateway)
+	accountGateway = "gateway"
+	// accountRevenue menampung pendapatan pembelian paket dengan wallet dan dikurangi saat refund-nya
+	accountRevenue = "revenue"
+	// accountAdjustments menampung penyesuaian saldo oleh admin
+	accountAdjustments = "adjustments"
+)
+
+// Posting adalah satu sisi jurnal yang akan dicatat
+type Posting struct {
+	Account models.LedgerAccount
+	Amount  int64
+}
+
+// Journal berisi keterangan jurnal yang akan dicatat
+type Journal struct {
+	Type        string
+	Reference   string
+	Description string
+	CreatedByID *uint
+}
+
+// WalletAccount mengembalikan akun wallet pengguna untuk mata uang tersebut, dan membuatnya jika belum ada
+func WalletAccount(tx *gorm.DB, userID uint, currency string) (models.LedgerAccount, error) {
+	return findOrCreateAccount(tx, models.LedgerAccount{
+		Code:     walletCode(userID, currency),
+		Kind:     models.LedgerAccountWallet,
+		UserID:   &userID,
+		Currency: currency,
+	})
+}
+
+// systemAccount mengembalikan akun sistem dengan nama tersebut, dan membuatnya jika belum ada
+func systemAccount(tx *gorm.DB, name, currency string) (models.LedgerAccount, error) {
+	return findOrCreateAccount(tx, models.LedgerAccount{
+		Code:     fmt.Sprintf("%s:%s:%s", models.LedgerAccountSystem, name, currency),
+		Kind:     models.LedgerAccountSystem,
+		Currency: currency,
+	})
+}
+
+// findOrCreateAccount memakai INSERT ... ON CONFLICT DO NOTHING agar pembuatan akun bersamaan tidak gagal
+func findOrCreateAccount(tx *gorm.DB, account models.LedgerAccount) (models.LedgerAccount, error) {
+	if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).Create(&account).Error; err != nil {
+		return account, err
+	}
+	var existing models.LedgerAccount
+	err := tx.Where("code = ?", account.Code).First(&existing).Error
+	return existing, err
+}
+
+// Balance menghitung saldo akun dari seluruh entri ledger-nya
+func Balance(db *gorm.DB, account models.LedgerAccount) (money.Money, error) {
+	var total int64
+	err := db.Model(&models.LedgerEntry{}).Select("COALESCE(SUM(amount), 0)").Where("account_id = ?", account.ID).Scan(&total).Error
+	return money.New(total, account.Currency), err
+}
+
+// Post mencatat jurnal double-entry di dalam transaksi tx. Jumlah seluruh posting harus nol dan
+// mata uangnya sama. Akun wallet yang saldonya berkurang dikunci (urut ID untuk mencegah deadlock)
+// lalu saldonya diperiksa, sehingga transaksi bersamaan tidak dapat membuat saldo wallet negatif.
+func Post(tx *gorm.DB, journal Journal, postings ...Posting) (models.JournalEntry, error) {
+	var sum int64
+	currency := ""
+	for _, p := range postings {
+		if p.Amount == 0 {
+			return models.JournalEntry{}, ErrInvalidAmount
+		}
+		if currency != "" && p.Account.Currency != currency {
+			return models.JournalEntry{}, money.ErrCurrencyMismatch
+		}
+		currency = p.Account.Currency
+		sum += p.Amount
+	}
+	if len(postings) < 2 || sum != 0 {
+		return models.JournalEntry{}, fmt.Errorf("unbalanced journal %s: postings sum to %d", journal.Type, sum)
+	}
+
+	debited := make([]Posting, 0, len(postings))
+	for _, p := range postings {
+		if p.Account.Kind == models.LedgerAccountWallet && p.Amount < 0 {
+			debited = append(debited, p)
+		}
+	}
+	sort.Slice(debited, func(i, j int) bool { return debited[i].Account.ID < debited[j].Account.ID })
+	for _, p := range debited {
+		var locked models.LedgerAccount
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", p.Account.ID).First(&locked).Error; err != nil {
+			return models.JournalEntry{}, err
+		}
+		balance, err := Balance(tx, locked)
+		if err != nil {
+			return models.JournalEntry{}, err
+		}
+		if balance.Amount+p.Amount < 0 {
+			return models.JournalEntry{}, ErrInsufficientFunds
+		}
+	}
+
+	entry := models.JournalEntry{
+		Type:        journal.Type,
+		Reference:   journal.Reference,
+		Description: journal.Description,
+		CreatedByID: journal.CreatedByID,
+	}
+	for _, p := range postings {
+		entry.Entries = append(entry.Entries, models.LedgerEntry{AccountID: p.Account.ID, Amount: p.Amount})
+	}
+	// Savepoint agar pelanggaran unique index referensi tidak membatalkan seluruh transaksi pemanggil
+	err := tx.SavePoint("journal").Error
+	if err == nil {
+		err = tx.Create(&entry).Error
+		if err != nil {
+			tx.RollbackTo("journal")
+		}
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return entry, ErrDuplicateJournal
+		}
+		return entry, err
+	}
+	return entry, nil
+}
+
+// TopUp menambah saldo wallet dari dana yang diterima payment gateway
+func TopUp(tx *gorm.DB, userID uint, amount money.Money, reference string) (models.JournalEntry, error) {
+	if !amount.IsPositive() {
+		return models.JournalEntry{}, ErrInvalidAmount
+	}
+	return transfer(tx, userID, accountGateway, amount, Journal{Type: models.JournalTopUp, Reference: reference})
+}
+
+// Purchase mengurangi saldo wallet sebesar amount untuk pembelian paket
+func Purchase(tx *gorm.DB, userID uint, amount money.Money, reference, description string) (models.JournalEntry, error) {
+	if !amount.IsPositive() {
+		return models.JournalEntry{}, ErrInvalidAmount
+	}
+	return transfer(tx, userID, accountRevenue, amount.Mul(-1),
+		Journal{Type: models.JournalPurchase, Reference: reference, Description: description})
+}
+
+// Refund mengembalikan nominal pembelian ke saldo wallet atas perintah admin. Lawan transaksinya mengikuti
+// cara order dibayar: pembelian dengan wallet dikembalikan dari akun revenue yang menerimanya, sedangkan
+// pembelian melalui payment gateway tidak pernah masuk ledger sehingga dananya dicatat dari akun gateway,
+// sama seperti top-up.
+func Refund(tx *gorm.DB, userID uint, amount money.Money, gateway, reference, reason string, adminID uint) (models.JournalEntry, error) {
+	if !amount.IsPositive() {
+		return models.JournalEntry{}, ErrInvalidAmount
+	}
+	counterAccount := accountGateway
+	if gateway == models.GatewayWallet {
+		counterAccount = accountRevenue
+	}
+	return transfer(tx, userID, counterAccount, amount,
+		Journal{Type: models.JournalRefund, Reference: reference, Description: reason, CreatedByID: &adminID})
+}
+
+// Adjust menambah (amount positif) atau mengurangi (amount negatif) saldo wallet atas perintah admin
+func Adjust(tx *gorm.DB, userID uint, amount money.Money, reason string, adminID uint) (models.JournalEntry, error) {
+	if amount.IsZero() {
+		return models.JournalEntry{}, ErrInvalidAmount
+	}
+	return transfer(tx, userID, accountAdjustments, amount,
+		Journal{Type: models.JournalAdjustment, Description: reason, CreatedByID: &adminID})
+}
+
+// transfer mencatat jurnal antara wallet pengguna (berubah sebesar amount) dan akun sistem lawannya
+func transfer(tx *gorm.DB, userID uint, counterAccount string, amount money.Money, journal Journal) (models.JournalEntry, error) {
+	walletAccount, err := WalletAccount(tx, userID, amount.Currency)
+	if err != nil {
+		return models.JournalEntry{}, err
+	}
+	counter, err := systemAccount(tx, counterAccount, amount.Currency)
+	if err != nil {
+		return models.JournalEntry{}, err
+	}
+	return Post(tx, journal,
+		Posting{Account: walletAccount, Amount: amount.Amount},
+		Posting{Account: counter, Amount: -amount.Amount},
+	)
+}
+
+// Transaction adalah satu mutasi wallet pengguna untuk riwayat transaksi
+type Transaction struct {
+	ID             uint      `json:"id"`
+	JournalEntryID uint      `json:"journal_entry_id"`
+	Type           string    `json:"type"`
+	Reference      string    `json:"reference,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	Amount         int64     `json:"amount"`
+	AmountDisplay  string    `json:"amount_display" gorm:"-"`
+	BalanceAfter   int64     `json:"balance_after"`
+	BalanceDisplay string    `json:"balance_after_display" gorm:"-"`
+	Currency       string    `json:"currency" gorm:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// UserBalance menghitung saldo wallet pengguna tanpa membuat akun wallet jika belum ada
+func UserBalance(db *gorm.DB, userID uint, currency string) (money.Money, error) {
+	var total int64
+	err := db.Model(&models.LedgerEntry{}).Select("COALESCE(SUM(ledger_entries.amount), 0)").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_accounts.code = ?", walletCode(userID, currency)).Scan(&total).Error
+	return money.New(total, currency), err
+}
+
+// History mengembalikan mutasi wallet pengguna dari yang terbaru beserta saldo setelah setiap mutasi,
+// dan jumlah seluruh mutasi untuk pagination
+func History(db *gorm.DB, userID uint, currency string, limit, offset int) ([]Transaction, int64, error) {
+	entries := db.Table("ledger_entries").
+		Select(`ledger_entries.id, ledger_entries.journal_entry_id, ledger_entries.amount, ledger_entries.created_at,
+			journal_entries.type, journal_entries.reference, journal_entries.description,
+			SUM(ledger_entries.amount) OVER (ORDER BY ledger_entries.id) AS balance_after`).
+		Joins("JOIN journal_entries ON journal_entries.id = ledger_entries.journal_entry_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_accounts.code = ?", walletCode(userID, currency))
+
+	var total int64
+	if err := db.Table("(?) AS entries", entries).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var transactions []Transaction
+	// Saldo berjalan dihitung di subquery atas seluruh mutasi sebelum LIMIT/OFFSET diterapkan
+	if err := db.Table("(?) AS entries", entries).Order("id DESC").Limit(limit).Offset(offset).Scan(&transactions).Error; err != nil {
+		return nil, 0, err
+	}
+	for i := range transactions {
+		transactions[i].Currency = currency
+		transactions[i].AmountDisplay = money.New(transactions[i].Amount, currency).Format()
+		transactions[i].BalanceDisplay = money.New(transactions[i].BalanceAfter, currency).Format()
+	}
+	return transactions, total, nil
+}
+
+func walletCode(userID uint, currency string) string {
+	return fmt.Sprintf("%s:%d:%s", models.LedgerAccountWallet, userID, currency)
+}